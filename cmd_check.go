@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/puerco/protobom/pkg/reader"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// errNotCompliant is returned when a document fails a compliance check
+var errNotCompliant = errors.New("document is not compliant")
+
+// runNTIA checks an SBOM for the NTIA minimum elements and prints a report
+func runNTIA(args []string) error {
+	return runCheck(append([]string{"-profile", sbom.NTIAProfile.ID}, args...))
+}
+
+// runCheck checks an SBOM against a compliance profile and prints a report
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	profileID := flags.String("profile", sbom.NTIAProfile.ID, "ID of the built in profile to check")
+	profileFile := flags.String("profile-file", "", "path to a JSON profile definition")
+	jsonOutput := flags.Bool("json", false, "output the report in JSON")
+	showGaps := flags.Bool("gaps", true, "list the elements missing in each node")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: check [-profile id | -profile-file profile.json] [-json] [-gaps=false] sbom.json")
+	}
+
+	profile, err := loadProfile(*profileID, *profileFile)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parseSBOM(reader.New(), flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	report := profile.Check(doc)
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("encoding report: %w", err)
+		}
+	} else {
+		printComplianceReport(report, *showGaps)
+	}
+
+	if !report.Compliant() {
+		return errNotCompliant
+	}
+	return nil
+}
+
+// loadProfile returns a built in profile or reads one from a file
+func loadProfile(id, path string) (*sbom.Profile, error) {
+	if path == "" {
+		return sbom.GetProfile(id)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening profile: %w", err)
+	}
+	defer f.Close()
+
+	profile, err := sbom.LoadProfile(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading profile from %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// printComplianceReport writes a compliance report as text to STDOUT
+func printComplianceReport(report *sbom.ComplianceReport, showGaps bool) {
+	fmt.Printf("%s compliance report\n\n", report.Profile)
+	for _, cov := range report.Coverage {
+		optional := ""
+		if !cov.Required {
+			optional = " (optional)"
+		}
+		fmt.Printf(
+			"  %-48s %6d/%-6d %6.1f%%%s\n",
+			cov.Element, cov.Covered, cov.Total, cov.Percentage(), optional,
+		)
+	}
+	fmt.Println()
+
+	if !showGaps {
+		return
+	}
+
+	for _, f := range report.Findings() {
+		fmt.Printf("  %s\n", f)
+	}
+}
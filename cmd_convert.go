@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/puerco/protobom/pkg/batch"
+	"github.com/puerco/protobom/pkg/writer"
+)
+
+// formatNames are the short names of the output formats in the CLI
+var formatNames = map[string]formats.Format{
+	"spdx":      formats.SPDX23JSON,
+	"cyclonedx": formats.CDX14JSON,
+	"cdx":       formats.CDX14JSON,
+}
+
+// runConvert converts a directory of SBOMs to another format and prints a
+// summary of the failures and the data lost
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	format := flags.String("format", "cyclonedx", "output format: spdx, cyclonedx or a format media type")
+	workers := flags.Int("workers", 0, "number of files converted at once, defaults to the number of CPUs")
+	jsonOutput := flags.Bool("json", false, "output the summary in JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: convert [-format spdx|cyclonedx] [-workers n] [-json] source-dir output-dir")
+	}
+
+	w := writer.New()
+	w.Options.Format = formats.Format(*format)
+	if f, ok := formatNames[*format]; ok {
+		w.Options.Format = f
+	}
+
+	summary, err := batch.ConvertDirectory(
+		context.Background(), flags.Arg(0), flags.Arg(1),
+		&batch.Options{Writer: w, Workers: *workers},
+	)
+	if err != nil {
+		return fmt.Errorf("converting directory: %w", err)
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(batchSummaryJSON(summary)); err != nil {
+			return fmt.Errorf("encoding summary: %w", err)
+		}
+	} else {
+		printBatchSummary(summary)
+	}
+
+	if len(summary.Failures) > 0 {
+		return fmt.Errorf("%d files failed to convert", len(summary.Failures))
+	}
+	return nil
+}
+
+// batchSummaryJSON returns the summary with the errors as strings
+func batchSummaryJSON(summary *batch.Summary) interface{} {
+	type failure struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	}
+	failures := []failure{}
+	for _, f := range summary.Failures {
+		failures = append(failures, failure{Path: f.Path, Error: f.Err.Error()})
+	}
+	return struct {
+		Converted   int                `json:"converted"`
+		Degraded    int                `json:"degraded"`
+		Failures    []failure          `json:"failures"`
+		Degradation writer.Degradation `json:"degradation"`
+	}{summary.Converted, summary.Degraded, failures, summary.Degradation}
+}
+
+// printBatchSummary writes a batch summary as text to STDOUT
+func printBatchSummary(summary *batch.Summary) {
+	for _, f := range summary.Failures {
+		fmt.Printf("FAILED %s\n", f)
+	}
+	if len(summary.Failures) > 0 {
+		fmt.Println()
+	}
+
+	fmt.Printf("  %-32s %8d\n", "converted", summary.Converted)
+	fmt.Printf("  %-32s %8d\n", "failed", len(summary.Failures))
+	fmt.Printf("  %-32s %8d\n", "degraded", summary.Degraded)
+	for _, kind := range summary.Degradation.Kinds() {
+		fmt.Printf("    %-30s %8d\n", kind, summary.Degradation[kind])
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/catalog/apk"
+	"github.com/puerco/protobom/pkg/catalog/cargo"
+	"github.com/puerco/protobom/pkg/catalog/dpkg"
+	"github.com/puerco/protobom/pkg/catalog/npm"
+	"github.com/puerco/protobom/pkg/catalog/python"
+	"github.com/puerco/protobom/pkg/generator/golang"
+	"github.com/puerco/protobom/pkg/generator/oci"
+	"github.com/puerco/protobom/pkg/sbom"
+	"github.com/puerco/protobom/pkg/writer"
+)
+
+// generators maps the kinds of artifacts to the functions that generate
+// their SBOMs.
+var generators = map[string]func(string) (*sbom.Document, error){
+	"gobinary": golang.FromBinary,
+	"gomod": func(path string) (*sbom.Document, error) {
+		return golang.FromModule(path, nil)
+	},
+	"image": func(path string) (*sbom.Document, error) {
+		return oci.FromImage(path, nil)
+	},
+	"project": func(path string) (*sbom.Document, error) {
+		return catalog.FromDirectory(path, npm.New(), python.New(), cargo.New())
+	},
+	"rootfs": func(path string) (*sbom.Document, error) {
+		return catalog.FromDirectory(path, apk.New(), dpkg.New())
+	},
+}
+
+// runGenerate creates an SBOM from an artifact and writes it to stdout
+func runGenerate(args []string) error {
+	kinds := []string{}
+	for k := range generators {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	usage := fmt.Sprintf("usage: generate [%s] path", strings.Join(kinds, "|"))
+
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New(usage)
+	}
+
+	generate, ok := generators[flags.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown artifact kind %q, %s", flags.Arg(0), usage)
+	}
+
+	doc, err := generate(flags.Arg(1))
+	if err != nil {
+		return fmt.Errorf("generating sbom: %w", err)
+	}
+
+	if err := writer.New().WriteStream(doc, os.Stdout); err != nil {
+		return fmt.Errorf("writing sbom to stdout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/puerco/protobom/pkg/license"
+	"github.com/puerco/protobom/pkg/reader"
+)
+
+// runLicense evaluates the licenses in an SBOM against a license policy
+func runLicense(args []string) error {
+	flags := flag.NewFlagSet("license", flag.ExitOnError)
+	policyFile := flags.String("policy", "", "path to the JSON license policy")
+	jsonOutput := flags.Bool("json", false, "output the violations in JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *policyFile == "" {
+		return fmt.Errorf("usage: license -policy policy.json [-json] sbom.json")
+	}
+
+	f, err := os.Open(*policyFile)
+	if err != nil {
+		return fmt.Errorf("opening policy: %w", err)
+	}
+	defer f.Close()
+
+	policy, err := license.LoadPolicy(f)
+	if err != nil {
+		return fmt.Errorf("loading policy: %w", err)
+	}
+
+	doc, err := parseSBOM(reader.New(), flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	violations, err := policy.Evaluate(doc)
+	if err != nil {
+		return fmt.Errorf("evaluating policy: %w", err)
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(violations); err != nil {
+			return fmt.Errorf("encoding violations: %w", err)
+		}
+	} else {
+		for _, v := range violations {
+			fmt.Println(v)
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d license policy violations", len(violations))
+	}
+	return nil
+}
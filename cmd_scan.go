@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/puerco/protobom/pkg/osv"
+	"github.com/puerco/protobom/pkg/reader"
+	"github.com/puerco/protobom/pkg/writer"
+)
+
+// runScan matches an SBOM against a local OSV database
+func runScan(args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	dbPath := flags.String("db", "", "OSV database directory or zip file")
+	jsonOutput := flags.Bool("json", false, "output the findings in JSON")
+	annotate := flags.Bool("annotate", false, "write the SBOM with the vulnerabilities found")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *dbPath == "" {
+		return fmt.Errorf("usage: scan -db osv.zip [-json|-annotate] sbom.json")
+	}
+
+	db, err := osv.Load(*dbPath)
+	if err != nil {
+		return fmt.Errorf("loading vulnerability database: %w", err)
+	}
+
+	doc, err := parseSBOM(reader.New(), flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	findings := db.Match(doc)
+
+	switch {
+	case *annotate:
+		doc.Vulnerabilities = append(doc.Vulnerabilities, osv.Vulnerabilities(findings)...)
+		if err := writer.New().WriteStream(doc, os.Stdout); err != nil {
+			return fmt.Errorf("writing sbom to stdout: %w", err)
+		}
+		return nil
+	case *jsonOutput:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return fmt.Errorf("encoding findings: %w", err)
+		}
+	default:
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d vulnerabilities", len(findings))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/puerco/protobom/pkg/attestation"
+	"github.com/puerco/protobom/pkg/reader"
+	"github.com/puerco/protobom/pkg/writer"
+	"github.com/sirupsen/logrus"
+)
+
+// runSign signs the canonical serialization of an SBOM and prints the
+// DSSE envelope
+func runSign(args []string) error {
+	flags := flag.NewFlagSet("sign", flag.ExitOnError)
+	keyFile := flags.String("key", "", "PEM file with the ed25519 or ECDSA P-256 private key")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *keyFile == "" {
+		return fmt.Errorf("usage: sign -key key.pem sbom.json")
+	}
+
+	key, err := attestation.LoadPrivateKey(*keyFile)
+	if err != nil {
+		return fmt.Errorf("loading private key: %w", err)
+	}
+
+	doc, err := parseSBOM(reader.New(), flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	env, err := attestation.SignDocument(doc, key)
+	if err != nil {
+		return fmt.Errorf("signing document: %w", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(env); err != nil {
+		return fmt.Errorf("encoding envelope: %w", err)
+	}
+	return nil
+}
+
+// runVerify verifies a signed SBOM envelope and writes the document
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	keyFile := flags.String("key", "", "PEM file with the ed25519 or ECDSA P-256 public key")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *keyFile == "" {
+		return fmt.Errorf("usage: verify -key key.pub envelope.json")
+	}
+
+	key, err := attestation.LoadPublicKey(*keyFile)
+	if err != nil {
+		return fmt.Errorf("loading public key: %w", err)
+	}
+
+	f := os.Stdin
+	if flags.Arg(0) != "-" {
+		f, err = os.Open(flags.Arg(0))
+		if err != nil {
+			return fmt.Errorf("opening envelope: %w", err)
+		}
+		defer f.Close()
+	}
+	env := &attestation.Envelope{}
+	if err := json.NewDecoder(f).Decode(env); err != nil {
+		return fmt.Errorf("decoding envelope: %w", err)
+	}
+
+	doc, err := attestation.VerifyDocument(env, key)
+	if err != nil {
+		return fmt.Errorf("verifying envelope: %w", err)
+	}
+	logrus.Infof("signature of %s verified", flags.Arg(0))
+
+	if err := writer.New().WriteStream(doc, os.Stdout); err != nil {
+		return fmt.Errorf("writing sbom to stdout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/puerco/protobom/pkg/reader"
+	"github.com/puerco/protobom/pkg/vex"
+	"github.com/puerco/protobom/pkg/writer"
+	"github.com/sirupsen/logrus"
+)
+
+// runVEX applies OpenVEX statements to an SBOM or generates an OpenVEX
+// document from the vulnerability data in an SBOM.
+func runVEX(args []string) error {
+	flags := flag.NewFlagSet("vex", flag.ExitOnError)
+	vexFile := flags.String("apply", "", "OpenVEX document to apply to the SBOM")
+	author := flags.String("author", "", "author of the generated OpenVEX document")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: vex [-apply vex.json | -author name] sbom.json")
+	}
+
+	doc, err := parseSBOM(reader.New(), flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	if *vexFile == "" {
+		vexDoc, err := vex.Generate(doc, &vex.Options{Author: *author})
+		if err != nil {
+			return fmt.Errorf("generating VEX document: %w", err)
+		}
+		return vexDoc.Write(os.Stdout)
+	}
+
+	vexDoc, err := vex.ParseFile(*vexFile)
+	if err != nil {
+		return fmt.Errorf("reading VEX document: %w", err)
+	}
+
+	result, err := vex.Apply(doc, vexDoc)
+	if err != nil {
+		return fmt.Errorf("applying VEX document: %w", err)
+	}
+
+	for _, s := range result.Unmatched {
+		logrus.Warnf("statement for %s did not match any node", s.Vulnerability.Name)
+	}
+
+	if err := writer.New().WriteStream(doc, os.Stdout); err != nil {
+		return fmt.Errorf("writing sbom to stdout: %w", err)
+	}
+	return nil
+}
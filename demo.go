@@ -14,16 +14,41 @@ import (
 
 var filename = filepath.Join(os.TempDir(), "sbom.proto")
 
+// commands maps the names of the subcommands to their entrypoints. When
+// the first argument is not a subcommand, it is treated as an SBOM to convert.
+var commands = map[string]func([]string) error{
+	"check":    runCheck,
+	"convert":  runConvert,
+	"generate": runGenerate,
+	"license":  runLicense,
+	"ntia":     runNTIA,
+	"scan":     runScan,
+	"sign":     runSign,
+	"verify":   runVerify,
+	"vex":      runVEX,
+}
+
 func main() {
+	if len(os.Args) < 2 {
+		logrus.Fatalf("usage: %s [check|convert|generate|license|ntia|scan|sign|verify|vex] sbom.json", os.Args[0])
+	}
+
+	if cmd, ok := commands[os.Args[1]]; ok {
+		if err := cmd(os.Args[2:]); err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
+
 	if len(os.Args) != 2 {
-		logrus.Fatal("usage: %s sbom.json")
+		logrus.Fatalf("usage: %s sbom.json", os.Args[0])
 	}
 
 	var doc *sbom.Document
 
 	parser := reader.New()
 
-	doc, err := parser.ParseFile(os.Args[1])
+	doc, err := parseSBOM(parser, os.Args[1])
 	if err != nil {
 		logrus.Fatalf("parsing file: %v", err)
 	}
@@ -40,14 +65,22 @@ func main() {
 	}
 }
 
+// parseSBOM parses the SBOM at path, a path of "-" reads it from STDIN
+func parseSBOM(parser *reader.Parser, path string) (*sbom.Document, error) {
+	if path == "-" {
+		return parser.ParseReader(os.Stdin)
+	}
+	return parser.ParseFile(path)
+}
+
 func writeProto(bom *sbom.Document) {
 	out, err := proto.Marshal(bom)
 	if err != nil {
-		logrus.Fatal("marshalling sbom to protobuf: %v", err)
+		logrus.Fatalf("marshalling sbom to protobuf: %v", err)
 	}
 
 	if err := os.WriteFile(filename, out, os.FileMode(0o644)); err != nil {
-		logrus.Fatal("writing data to disk: %v", err)
+		logrus.Fatalf("writing data to disk: %v", err)
 	}
 }
 
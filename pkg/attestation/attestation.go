@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package attestation reads and writes in-toto statements carrying SBOMs
+// as their predicates and the DSSE envelopes wrapping them.
+package attestation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+const (
+	// PayloadType is the DSSE payload type of in-toto statements
+	PayloadType = "application/vnd.in-toto+json"
+
+	StatementTypeV01 = "https://in-toto.io/Statement/v0.1"
+	StatementTypeV1  = "https://in-toto.io/Statement/v1"
+
+	PredicateTypeSPDX      = "https://spdx.dev/Document"
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+)
+
+// Kinds of wrappers detected by DetectWrapper
+const (
+	WrapperNone      = ""
+	WrapperEnvelope  = "dsse"
+	WrapperStatement = "in-toto"
+)
+
+// Subject is an artifact the statement refers to
+type Subject struct {
+	Name   string            `json:"name,omitempty"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Statement is an in-toto statement
+type Statement struct {
+	Type          string          `json:"_type"`
+	Subject       []Subject       `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// Envelope is a DSSE envelope
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// DetectWrapper returns the kind of wrapper of a JSON document from the
+// first key of its top level object. The data may be the start of the
+// document as long as it has the first key.
+func DetectWrapper(data []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return WrapperNone
+	}
+	t, err := dec.Token()
+	if err != nil {
+		return WrapperNone
+	}
+	switch t {
+	case "payloadType", "payload", "signatures":
+		return WrapperEnvelope
+	case "_type", "predicateType", "subject", "predicate":
+		return WrapperStatement
+	default:
+		return WrapperNone
+	}
+}
+
+// DecodePayload returns the decoded payload of the envelope
+func (e *Envelope) DecodePayload() ([]byte, error) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if data, err := enc.DecodeString(e.Payload); err == nil {
+			return data, nil
+		}
+	}
+	return nil, errors.New("payload is not base64 encoded")
+}
+
+// Unwrap decodes a DSSE envelope or a bare in-toto statement and returns
+// the statement. Signatures are not verified.
+func Unwrap(data []byte) (*Statement, error) {
+	if DetectWrapper(data) == WrapperEnvelope {
+		env := &Envelope{}
+		if err := json.Unmarshal(data, env); err != nil {
+			return nil, fmt.Errorf("decoding envelope: %w", err)
+		}
+		if env.PayloadType != PayloadType {
+			return nil, fmt.Errorf("envelope payload type %q is not an in-toto statement", env.PayloadType)
+		}
+		payload, err := env.DecodePayload()
+		if err != nil {
+			return nil, fmt.Errorf("decoding envelope payload: %w", err)
+		}
+		data = payload
+	}
+
+	st := &Statement{}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("decoding statement: %w", err)
+	}
+	if st.Type != StatementTypeV1 && st.Type != StatementTypeV01 {
+		return nil, fmt.Errorf("unknown statement type %q", st.Type)
+	}
+	return st, nil
+}
+
+// IsSBOMPredicate returns true if the predicate type is an SPDX or
+// CycloneDX document, with or without a version suffix.
+func IsSBOMPredicate(predicateType string) bool {
+	for _, t := range []string{PredicateTypeSPDX, PredicateTypeCycloneDX} {
+		if predicateType == t || strings.HasPrefix(predicateType, t+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SBOM returns the SBOM in the statement predicate. Predicates encoded as
+// a JSON string are unquoted.
+func (s *Statement) SBOM() ([]byte, error) {
+	if !IsSBOMPredicate(s.PredicateType) {
+		return nil, fmt.Errorf("predicate type %q is not an SBOM", s.PredicateType)
+	}
+	predicate := bytes.TrimSpace(s.Predicate)
+	if len(predicate) == 0 || bytes.Equal(predicate, []byte("null")) {
+		return nil, errors.New("statement has no predicate")
+	}
+	if predicate[0] == '"' {
+		str := ""
+		if err := json.Unmarshal(predicate, &str); err != nil {
+			return nil, fmt.Errorf("decoding predicate string: %w", err)
+		}
+		return []byte(str), nil
+	}
+	return predicate, nil
+}
+
+// SubjectsToProto converts statement subjects to protobom subjects
+func SubjectsToProto(subjects []Subject) []*sbom.Subject {
+	ret := []*sbom.Subject{}
+	for _, sub := range subjects {
+		digest := map[string]string{}
+		for algo, value := range sub.Digest {
+			digest[algo] = value
+		}
+		ret = append(ret, &sbom.Subject{Name: sub.Name, Digest: digest})
+	}
+	return ret
+}
+
+// SubjectsFromProto converts protobom subjects to statement subjects
+func SubjectsFromProto(subjects []*sbom.Subject) []Subject {
+	ret := []Subject{}
+	for _, sub := range subjects {
+		digest := map[string]string{}
+		for algo, value := range sub.Digest {
+			digest[algo] = value
+		}
+		ret = append(ret, Subject{Name: sub.Name, Digest: digest})
+	}
+	return ret
+}
+
+// SubjectsFromDocument returns the subjects of a statement about the
+// document. The subjects the document was read with are reused, otherwise
+// they are the root nodes with hashes.
+func SubjectsFromDocument(doc *sbom.Document) []Subject {
+	if len(doc.Subjects) > 0 {
+		return SubjectsFromProto(doc.Subjects)
+	}
+
+	nodes := map[string]*sbom.Node{}
+	for _, n := range doc.Nodes {
+		nodes[n.Id] = n
+	}
+	subjects := []Subject{}
+	for _, id := range doc.RootElements {
+		n, ok := nodes[id]
+		if !ok || len(n.Hashes) == 0 {
+			continue
+		}
+		digest := map[string]string{}
+		for algo, value := range n.Hashes {
+			digest[DigestAlgorithm(algo)] = strings.ToLower(value)
+		}
+		name := n.Name
+		if name == "" {
+			name = n.Id
+		}
+		subjects = append(subjects, Subject{Name: name, Digest: digest})
+	}
+	return subjects
+}
+
+// digestAlgorithms are the in-toto names of the algorithms that have a
+// separator, keyed by their normalized name.
+var digestAlgorithms = map[string]string{
+	"SHA512224": "sha512_224",
+	"SHA512256": "sha512_256",
+	"SHA3224":   "sha3_224",
+	"SHA3256":   "sha3_256",
+	"SHA3384":   "sha3_384",
+	"SHA3512":   "sha3_512",
+}
+
+// DigestAlgorithm returns the in-toto name of a hash algorithm
+func DigestAlgorithm(algo string) string {
+	algo = sbom.NormalizeHashAlgorithm(algo)
+	if name, ok := digestAlgorithms[algo]; ok {
+		return name
+	}
+	return strings.ToLower(algo)
+}
+
+// NewStatement returns an in-toto v1 statement with an SBOM predicate
+func NewStatement(predicateType string, subjects []Subject, predicate []byte) *Statement {
+	return &Statement{
+		Type:          StatementTypeV1,
+		Subject:       subjects,
+		PredicateType: predicateType,
+		Predicate:     predicate,
+	}
+}
+
+// NewEnvelope returns an unsigned DSSE envelope with the statement as its
+// payload. Signatures can be added by external signers.
+func NewEnvelope(statement *Statement) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("encoding statement: %w", err)
+	}
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{},
+	}, nil
+}
@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package attestation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+)
+
+// PayloadTypeDocument is the DSSE payload type of the canonical protobuf
+// serialization of a protobom document.
+const PayloadTypeDocument = "application/vnd.protobom.document+protobuf"
+
+// ErrVerification is returned when no signature of an envelope verifies
+var ErrVerification = errors.New("signature verification failed")
+
+// PAE returns the DSSE pre-authentication encoding of a payload, the
+// message that is signed.
+func PAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf(
+		"DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload,
+	))
+}
+
+// LoadPrivateKey reads an ed25519 or ECDSA P-256 private key from a PEM
+// file. Keys are read in PKCS #8 or, for ECDSA, SEC 1 form.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.New("only ECDSA keys on the P-256 curve are supported")
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// LoadPublicKey reads an ed25519 or ECDSA P-256 public key in PKIX form
+// from a PEM file.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.New("only ECDSA keys on the P-256 curve are supported")
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// readPEM returns the first PEM block of a file
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
+
+// KeyID returns the ID of a public key, the hex SHA-256 digest of its
+// PKIX encoding.
+func KeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("encoding public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Sign signs the envelope payload and adds the signature to the envelope
+func (e *Envelope) Sign(signer crypto.Signer) error {
+	payload, err := e.DecodePayload()
+	if err != nil {
+		return fmt.Errorf("decoding payload: %w", err)
+	}
+	keyID, err := KeyID(signer.Public())
+	if err != nil {
+		return err
+	}
+
+	message := PAE(e.PayloadType, payload)
+	var sig []byte
+	switch signer.(type) {
+	case ed25519.PrivateKey:
+		sig, err = signer.Sign(rand.Reader, message, crypto.Hash(0))
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return fmt.Errorf("unsupported signer type %T", signer)
+	}
+	if err != nil {
+		return fmt.Errorf("signing payload: %w", err)
+	}
+
+	e.Signatures = append(e.Signatures, Signature{
+		KeyID: keyID,
+		Sig:   base64.StdEncoding.EncodeToString(sig),
+	})
+	return nil
+}
+
+// Verify checks that one of the envelope signatures was made with the key.
+// Signatures with a key ID of another key are skipped.
+func (e *Envelope) Verify(pub crypto.PublicKey) error {
+	payload, err := e.DecodePayload()
+	if err != nil {
+		return fmt.Errorf("decoding payload: %w", err)
+	}
+	keyID, err := KeyID(pub)
+	if err != nil {
+		return err
+	}
+
+	message := PAE(e.PayloadType, payload)
+	for _, s := range e.Signatures {
+		if s.KeyID != "" && s.KeyID != keyID {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		switch k := pub.(type) {
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, message, sig) {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			digest := sha256.Sum256(message)
+			if ecdsa.VerifyASN1(k, digest[:], sig) {
+				return nil
+			}
+		default:
+			return fmt.Errorf("unsupported public key type %T", pub)
+		}
+	}
+	return ErrVerification
+}
+
+// SignDocument returns a DSSE envelope with the canonical serialization of
+// the document as its payload, signed with the key.
+func SignDocument(doc *sbom.Document, signer crypto.Signer) (*Envelope, error) {
+	payload, err := doc.MarshalCanonical()
+	if err != nil {
+		return nil, fmt.Errorf("serializing document: %w", err)
+	}
+	env := &Envelope{
+		PayloadType: PayloadTypeDocument,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{},
+	}
+	if err := env.Sign(signer); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// VerifyDocument verifies an envelope signed with SignDocument and returns
+// the document in its payload.
+func VerifyDocument(env *Envelope, pub crypto.PublicKey) (*sbom.Document, error) {
+	if env.PayloadType != PayloadTypeDocument {
+		return nil, fmt.Errorf("envelope payload type %q is not a protobom document", env.PayloadType)
+	}
+	if err := env.Verify(pub); err != nil {
+		return nil, err
+	}
+	payload, err := env.DecodePayload()
+	if err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+	doc := &sbom.Document{}
+	if err := proto.Unmarshal(payload, doc); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	return doc, nil
+}
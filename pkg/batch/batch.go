@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package batch converts trees of SBOM files between formats
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/puerco/protobom/pkg/reader"
+	"github.com/puerco/protobom/pkg/writer"
+)
+
+// extensions are the file extensions written for each format
+var extensions = map[formats.Format]string{
+	formats.SPDX23JSON: ".spdx.json",
+	formats.CDX14JSON:  ".cdx.json",
+}
+
+// sourceExtensions are removed from the input names before adding the
+// extension of the output format
+var sourceExtensions = []string{
+	".spdx.json", ".cdx.json", ".cyclonedx.json", ".bom.json", ".json",
+}
+
+// Options controls a batch conversion
+type Options struct {
+	// Parser reads the input files. Defaults to reader.New().
+	Parser *reader.Parser
+
+	// Writer renders the output files in the format of its options.
+	// Defaults to writer.New().
+	Writer *writer.Writer
+
+	// Workers is the number of files converted at once. Defaults to the
+	// number of CPUs.
+	Workers int
+}
+
+// Failure is a file that could not be converted
+type Failure struct {
+	Path string
+	Err  error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Path, f.Err)
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// Summary is the result of a batch conversion
+type Summary struct {
+	// Converted is the number of files written
+	Converted int
+
+	// Failures are the files that failed to convert, sorted by path
+	Failures []*Failure
+
+	// Degradation totals the data lost in the conversion of all files
+	Degradation writer.Degradation
+
+	// Degraded is the number of files that lost data in the conversion
+	Degraded int
+}
+
+// OutputPath returns the relative path of the converted file of an input
+// file. The SBOM extension is replaced by the one of the format, gzip
+// compressed inputs are written compressed.
+func OutputPath(rel string, format formats.Format) string {
+	gz := strings.HasSuffix(rel, ".gz")
+	rel = strings.TrimSuffix(rel, ".gz")
+	for _, ext := range sourceExtensions {
+		if strings.HasSuffix(rel, ext) {
+			rel = strings.TrimSuffix(rel, ext)
+			break
+		}
+	}
+	ext, ok := extensions[format]
+	if !ok {
+		ext = ".json"
+	}
+	rel += ext
+	if gz {
+		rel += ".gz"
+	}
+	return rel
+}
+
+// ConvertDirectory converts all the files in the src tree and writes them
+// to the same relative paths in dst. Files are converted by a pool of
+// workers. A file that fails to convert is recorded in the summary without
+// stopping the batch, an error is only returned if the tree can't be
+// walked or the context is cancelled. Files that would be written to the
+// output of a file found before them in the walk are failures.
+func ConvertDirectory(ctx context.Context, src, dst string, opts *Options) (*Summary, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	parser := opts.Parser
+	if parser == nil {
+		parser = reader.New()
+	}
+	w := opts.Writer
+	if w == nil {
+		w = writer.New()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	summary := &Summary{Failures: []*Failure{}, Degradation: writer.Degradation{}}
+	var mtx sync.Mutex
+	fail := func(rel string, err error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		summary.Failures = append(summary.Failures, &Failure{Path: rel, Err: err})
+	}
+	convert := func(rel string) {
+		deg, err := convertFile(parser, w, filepath.Join(src, rel), filepath.Join(dst, OutputPath(rel, w.Options.Format)))
+		if err != nil {
+			fail(rel, err)
+			return
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		summary.Converted++
+		if deg.Total() > 0 {
+			summary.Degraded++
+			summary.Degradation.Merge(deg)
+		}
+	}
+
+	paths := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rel := range paths {
+				convert(rel)
+			}
+		}()
+	}
+
+	outputs := map[string]string{}
+	walkErr := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// The output tree may be in the source tree
+		if d.IsDir() && filepath.Clean(path) == filepath.Clean(dst) {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		// Inputs differing only in their SBOM extension have the same
+		// output, only the first one found is converted
+		out := OutputPath(rel, w.Options.Format)
+		if first, ok := outputs[out]; ok {
+			fail(rel, fmt.Errorf("output %s is already written from %s", out, first))
+			return nil
+		}
+		outputs[out] = rel
+		select {
+		case paths <- rel:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(paths)
+	wg.Wait()
+
+	sort.Slice(summary.Failures, func(i, j int) bool {
+		return summary.Failures[i].Path < summary.Failures[j].Path
+	})
+	if walkErr != nil {
+		if errors.Is(walkErr, ctx.Err()) {
+			return summary, walkErr
+		}
+		return summary, fmt.Errorf("walking %s: %w", src, walkErr)
+	}
+	return summary, nil
+}
+
+// convertFile converts a single file, removing the output if it fails
+func convertFile(parser *reader.Parser, w *writer.Writer, src, dst string) (writer.Degradation, error) {
+	doc, err := parser.ParseFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), os.FileMode(0o755)); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	deg, err := w.WriteFileReport(doc, dst)
+	if err != nil {
+		os.Remove(dst)
+		return nil, fmt.Errorf("writing: %w", err)
+	}
+	return deg, nil
+}
@@ -0,0 +1,320 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package apk catalogs the packages in the apk installed database used by
+// Alpine and Wolfi.
+package apk
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// InstalledDB is the path of the apk installed database in a rootfs
+const InstalledDB = "lib/apk/db/installed"
+
+// Package is an entry in the apk installed database
+type Package struct {
+	Name         string
+	Version      string
+	Architecture string
+	Description  string
+	URL          string
+	License      string
+	Origin       string
+	Maintainer   string
+	Commit       string
+	BuildTime    time.Time
+
+	// Dependencies are the raw D: entries, Provides the p: entries
+	Dependencies []string
+	Provides     []string
+	Files        []*File
+}
+
+// File is a file owned by a package
+type File struct {
+	Path string
+
+	// SHA1 is the hex encoded checksum of the file, when recorded
+	SHA1 string
+}
+
+// Cataloger reads the apk installed database
+type Cataloger struct{}
+
+// New returns a new apk cataloger
+func New() *Cataloger {
+	return &Cataloger{}
+}
+
+// Name returns the name of the cataloger
+func (c *Cataloger) Name() string {
+	return "apk"
+}
+
+// Catalog reads the packages in the apk database of the filesystem. It
+// returns an empty document if there is no database.
+func (c *Cataloger) Catalog(fsys fs.FS) (*sbom.Document, error) {
+	doc := catalog.NewDocument()
+	f, err := fsys.Open(InstalledDB)
+	if errors.Is(err, fs.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening apk database: %w", err)
+	}
+	defer f.Close()
+
+	packages, err := ParseInstalled(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing apk database: %w", err)
+	}
+
+	osRelease, err := catalog.ReadOSRelease(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("reading os-release: %w", err)
+	}
+
+	nodes := map[*Package]*sbom.Node{}
+	for _, p := range packages {
+		n := packageNode(p, osRelease)
+		nodes[p] = n
+		doc.Nodes = append(doc.Nodes, n)
+
+		files := []string{}
+		for _, pf := range p.Files {
+			fn := catalog.FileNode(pf.Path)
+			if pf.SHA1 != "" {
+				fn.Hashes["SHA1"] = pf.SHA1
+			}
+			doc.Nodes = append(doc.Nodes, fn)
+			files = append(files, fn.Id)
+		}
+		if len(files) > 0 {
+			doc.Edges = append(doc.Edges, &sbom.Edge{
+				Type: sbom.Edge_contains,
+				From: n.Id,
+				To:   files,
+			})
+		}
+	}
+
+	providers := providerIndex(packages)
+	for _, p := range packages {
+		deps := []string{}
+		seen := map[string]struct{}{}
+		for _, d := range p.Dependencies {
+			provider, ok := providers[dependencyName(d)]
+			if !ok || provider == p {
+				continue
+			}
+			id := nodes[provider].Id
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			deps = append(deps, id)
+		}
+		if len(deps) > 0 {
+			doc.Edges = append(doc.Edges, &sbom.Edge{
+				Type: sbom.Edge_dependsOn,
+				From: nodes[p].Id,
+				To:   deps,
+			})
+		}
+	}
+	return doc, nil
+}
+
+// providerIndex maps the names that can satisfy a dependency to the package
+// providing them: package names, the names in the provides of each package
+// (so:, cmd:, pc: and virtual packages) and the paths of the files owned.
+func providerIndex(packages []*Package) map[string]*Package {
+	providers := map[string]*Package{}
+	for _, p := range packages {
+		for _, pf := range p.Files {
+			providers[pf.Path] = p
+		}
+		for _, name := range p.Provides {
+			if _, ok := providers[dependencyName(name)]; !ok {
+				providers[dependencyName(name)] = p
+			}
+		}
+	}
+	// Real package names take precedence over the provides
+	for _, p := range packages {
+		providers[p.Name] = p
+	}
+	return providers
+}
+
+// dependencyName strips the version constraint from a dependency or
+// provides entry, eg "so:libc.musl-x86_64.so.1=1" or "musl>=1.2". Conflicts
+// (entries starting with !) return an empty name.
+func dependencyName(d string) string {
+	if strings.HasPrefix(d, "!") {
+		return ""
+	}
+	if i := strings.IndexAny(d, "<>=~"); i != -1 {
+		d = d[:i]
+	}
+	return d
+}
+
+// ParseInstalled parses the apk installed database. Each package is a block
+// of "K:value" lines, packages are separated by blank lines.
+func ParseInstalled(r io.Reader) ([]*Package, error) {
+	packages := []*Package{}
+	var current *Package
+	var file *File
+	dir := ""
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || len(key) != 1 {
+			continue
+		}
+		if current == nil {
+			current = &Package{
+				Dependencies: []string{},
+				Provides:     []string{},
+				Files:        []*File{},
+			}
+			packages = append(packages, current)
+			file = nil
+			dir = ""
+		}
+		switch key {
+		case "P":
+			current.Name = value
+		case "V":
+			current.Version = value
+		case "A":
+			current.Architecture = value
+		case "T":
+			current.Description = value
+		case "U":
+			current.URL = value
+		case "L":
+			current.License = value
+		case "o":
+			current.Origin = value
+		case "m":
+			current.Maintainer = value
+		case "c":
+			current.Commit = value
+		case "t":
+			if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.BuildTime = time.Unix(secs, 0).UTC()
+			}
+		case "D":
+			current.Dependencies = append(current.Dependencies, strings.Fields(value)...)
+		case "p":
+			current.Provides = append(current.Provides, strings.Fields(value)...)
+		case "F":
+			dir = value
+			file = nil
+		case "R":
+			file = &File{Path: "/" + strings.TrimPrefix(dir+"/"+value, "/")}
+			current.Files = append(current.Files, file)
+		case "Z":
+			if file != nil {
+				file.SHA1 = decodeChecksum(value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading apk database: %w", err)
+	}
+	return packages, nil
+}
+
+// decodeChecksum converts an apk checksum to hex. SHA1 checksums are
+// recorded base64 encoded with a Q1 prefix. Other checksums are ignored.
+func decodeChecksum(s string) string {
+	if !strings.HasPrefix(s, "Q1") {
+		return ""
+	}
+	data, err := base64.StdEncoding.DecodeString(s[2:])
+	if err != nil || len(data) != 20 {
+		return ""
+	}
+	return hex.EncodeToString(data)
+}
+
+// packageNode returns the node of an apk package
+func packageNode(p *Package, osRelease *catalog.OSRelease) *sbom.Node {
+	n := &sbom.Node{
+		Id:                 catalog.PackageNodeID(p.Name, p.Version),
+		Type:               sbom.Node_PACKAGE,
+		Name:               p.Name,
+		Version:            p.Version,
+		Description:        p.Description,
+		UrlHome:            p.URL,
+		Licenses:           []string{},
+		Hashes:             map[string]string{},
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+	if p.License != "" {
+		n.Licenses = append(n.Licenses, p.License)
+	}
+	if maintainer := catalog.ParsePerson(p.Maintainer); maintainer != nil {
+		n.Suppliers = append(n.Suppliers, maintainer)
+	}
+	if !p.BuildTime.IsZero() {
+		n.BuildDate = timestamppb.New(p.BuildTime)
+	}
+
+	source := []string{}
+	if p.Origin != "" {
+		source = append(source, "built from origin package "+p.Origin)
+	}
+	if p.Commit != "" {
+		source = append(source, "at commit "+p.Commit)
+	}
+	n.SourceInfo = strings.Join(source, " ")
+
+	namespace := "alpine"
+	qualifiers := map[string]string{}
+	if p.Architecture != "" {
+		qualifiers["arch"] = p.Architecture
+	}
+	if p.Origin != "" && p.Origin != p.Name {
+		qualifiers["upstream"] = p.Origin
+	}
+	if osRelease != nil && osRelease.ID != "" {
+		namespace = osRelease.ID
+		if osRelease.VersionID != "" {
+			qualifiers["distro"] = osRelease.Distro()
+		}
+	}
+	n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+		Type:  "purl",
+		Value: purl.New("apk", namespace, p.Name, p.Version, qualifiers).String(),
+	})
+	return n
+}
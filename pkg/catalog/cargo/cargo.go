@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cargo catalogs the Rust crates locked in Cargo.lock files
+package cargo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/catalog/internal/toml"
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// cratesIO is the source of the crates from the crates.io registry
+const cratesIO = "registry+https://github.com/rust-lang/crates.io-index"
+
+// Package is a crate locked in Cargo.lock
+type Package struct {
+	Name     string
+	Version  string
+	Source   string
+	Checksum string
+
+	// Dependencies are entries in the "name", "name version" or
+	// "name version (source)" forms.
+	Dependencies []string
+}
+
+// Parser reads Cargo lockfiles
+type Parser struct{}
+
+// New returns a cataloger of Cargo lockfiles
+func New() *catalog.LockfileCataloger {
+	return catalog.NewLockfileCataloger(&Parser{})
+}
+
+// Name returns the name of the ecosystem
+func (p *Parser) Name() string {
+	return "cargo"
+}
+
+// Match returns true for Cargo.lock files
+func (p *Parser) Match(name string) bool {
+	return path.Base(name) == "Cargo.lock"
+}
+
+// Parse reads a lockfile. Crates without a source are the packages of the
+// workspace. The scopes of the dependencies of the package defined in the
+// Cargo.toml next to the lockfile are read from the manifest.
+func (p *Parser) Parse(fsys fs.FS, name string) (*sbom.Document, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening lockfile: %w", err)
+	}
+	defer f.Close()
+	lock, err := toml.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding lockfile: %w", err)
+	}
+	packages := ParseLockfile(lock)
+
+	manifest, err := readManifest(fsys, path.Join(path.Dir(name), "Cargo.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := catalog.NewDocument()
+	nodes := map[*Package]*sbom.Node{}
+	byName := map[string][]*Package{}
+	for _, pkg := range packages {
+		n := packageNode(pkg)
+		nodes[pkg] = n
+		byName[pkg.Name] = append(byName[pkg.Name], pkg)
+		doc.Nodes = append(doc.Nodes, n)
+	}
+
+	for _, pkg := range packages {
+		edges := map[sbom.Edge_Type][]string{}
+		for _, dep := range pkg.Dependencies {
+			target := resolve(byName, dep)
+			if target == nil {
+				continue
+			}
+			edgeType := sbom.Edge_dependsOn
+			if manifest != nil && pkg.Source == "" && pkg.Name == manifest.name {
+				if t, ok := manifest.scopes[target.Name]; ok {
+					edgeType = t
+				}
+			}
+			edges[edgeType] = append(edges[edgeType], nodes[target].Id)
+		}
+		for _, edgeType := range []sbom.Edge_Type{
+			sbom.Edge_dependsOn, sbom.Edge_optionalDependency, sbom.Edge_buildDependency, sbom.Edge_devDependency,
+		} {
+			doc.Edges = append(doc.Edges, catalog.DependencyEdges(edgeType, nodes[pkg].Id, edges[edgeType])...)
+		}
+	}
+	return doc, nil
+}
+
+// ParseLockfile returns the crates in a decoded Cargo.lock. The checksums
+// of version 1 lockfiles are read from the metadata table.
+func ParseLockfile(lock map[string]interface{}) []*Package {
+	metadata := toml.Table(lock, "metadata")
+	packages := []*Package{}
+	for _, entry := range toml.Tables(lock, "package") {
+		pkg := &Package{
+			Name:         toml.String(entry, "name"),
+			Version:      toml.String(entry, "version"),
+			Source:       toml.String(entry, "source"),
+			Checksum:     toml.String(entry, "checksum"),
+			Dependencies: toml.Strings(entry, "dependencies"),
+		}
+		if pkg.Name == "" {
+			continue
+		}
+		if pkg.Checksum == "" && metadata != nil {
+			key := fmt.Sprintf("checksum %s %s (%s)", pkg.Name, pkg.Version, pkg.Source)
+			if sum := toml.String(metadata, key); sum != "<none>" {
+				pkg.Checksum = sum
+			}
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// resolve finds the crate of a dependency entry. The version and source
+// are only recorded when several crates have the same name.
+func resolve(byName map[string][]*Package, dep string) *Package {
+	fields := strings.Fields(dep)
+	if len(fields) == 0 {
+		return nil
+	}
+	candidates := byName[fields[0]]
+	for _, c := range candidates {
+		if len(fields) > 1 && c.Version != fields[1] {
+			continue
+		}
+		if len(fields) > 2 && strings.Trim(fields[2], "()") != c.Source {
+			continue
+		}
+		return c
+	}
+	return nil
+}
+
+// manifest is the data of Cargo.toml used to scope the dependencies
+type manifest struct {
+	name   string
+	scopes map[string]sbom.Edge_Type
+}
+
+// readManifest reads the package name and the dependency scopes from a
+// Cargo.toml. Renamed dependencies are keyed by the package name. It
+// returns nil if the file does not exist or defines no package.
+func readManifest(fsys fs.FS, name string) (*manifest, error) {
+	f, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening Cargo.toml: %w", err)
+	}
+	defer f.Close()
+	data, err := toml.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding Cargo.toml: %w", err)
+	}
+
+	m := &manifest{
+		name:   toml.String(toml.Table(data, "package"), "name"),
+		scopes: map[string]sbom.Edge_Type{},
+	}
+	if m.name == "" {
+		return nil, nil
+	}
+	// Normal dependencies take precedence when a crate is listed in
+	// several tables, so they are recorded last.
+	for _, scope := range []struct {
+		table    string
+		edgeType sbom.Edge_Type
+	}{
+		{"dev-dependencies", sbom.Edge_devDependency},
+		{"build-dependencies", sbom.Edge_buildDependency},
+		{"dependencies", sbom.Edge_dependsOn},
+	} {
+		for dep, spec := range toml.Table(data, scope.table) {
+			edgeType := scope.edgeType
+			if t, ok := spec.(map[string]interface{}); ok {
+				if pkg := toml.String(t, "package"); pkg != "" {
+					dep = pkg
+				}
+				if toml.Bool(t, "optional") {
+					edgeType = sbom.Edge_optionalDependency
+				}
+			}
+			m.scopes[dep] = edgeType
+		}
+	}
+	return m, nil
+}
+
+// packageNode returns the node of a crate
+func packageNode(pkg *Package) *sbom.Node {
+	n := &sbom.Node{
+		Id:                 catalog.PackageNodeID("cargo/"+pkg.Name, pkg.Version),
+		Type:               sbom.Node_PACKAGE,
+		Name:               pkg.Name,
+		Version:            pkg.Version,
+		Licenses:           []string{},
+		Hashes:             map[string]string{},
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+	if pkg.Checksum != "" {
+		n.Hashes["SHA256"] = pkg.Checksum
+	}
+
+	qualifiers := map[string]string{}
+	switch {
+	case pkg.Source == cratesIO:
+		n.UrlDownload = fmt.Sprintf("https://crates.io/api/v1/crates/%s/%s/download", pkg.Name, pkg.Version)
+	case strings.HasPrefix(pkg.Source, "git+"):
+		n.UrlDownload = strings.TrimPrefix(pkg.Source, "git+")
+		qualifiers["vcs_url"] = pkg.Source
+	case strings.HasPrefix(pkg.Source, "registry+"):
+		qualifiers["repository_url"] = strings.TrimPrefix(pkg.Source, "registry+")
+	}
+	n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+		Type:  "purl",
+		Value: purl.New("cargo", "", pkg.Name, pkg.Version, qualifiers).String(),
+	})
+	return n
+}
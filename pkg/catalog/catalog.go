@@ -0,0 +1,396 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package catalog defines the interface of the catalogers, which find the
+// packages installed in a filesystem, and the helpers they share.
+package catalog
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Cataloger finds packages in a filesystem
+type Cataloger interface {
+	// Name returns the name of the cataloger
+	Name() string
+
+	// Catalog returns a document with the packages found in the filesystem,
+	// the files they own and the relationships among them. The document
+	// has no root elements, the packages are its top level nodes.
+	Catalog(fsys fs.FS) (*sbom.Document, error)
+}
+
+// NewDocument returns an empty document to be filled by a cataloger
+func NewDocument() *sbom.Document {
+	return &sbom.Document{
+		Metadata: &sbom.Metadata{
+			Tools:   []*sbom.Tool{},
+			Authors: []*sbom.Person{},
+		},
+		RootElements: []string{},
+		Nodes:        []*sbom.Node{},
+		Edges:        []*sbom.Edge{},
+	}
+}
+
+// escapeID replaces the characters not allowed in SPDX identifiers. Slashes
+// become dashes, other characters are replaced by C and their code, the
+// same scheme apko uses (eg "_" becomes "C95").
+func escapeID(s string) string {
+	sb := strings.Builder{}
+	for _, r := range s {
+		switch {
+		case r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-'):
+			sb.WriteRune(r)
+		case r == '/':
+			sb.WriteByte('-')
+		default:
+			sb.WriteString("C" + strconv.Itoa(int(r)))
+		}
+	}
+	return sb.String()
+}
+
+// FileNodeID returns the ID of the node of a file. Paths are absolute
+// in the filesystem being cataloged.
+func FileNodeID(path string) string {
+	return "File-" + escapeID("/"+strings.TrimPrefix(path, "/"))
+}
+
+// PackageNodeID returns the ID of the node of a package
+func PackageNodeID(name, version string) string {
+	if version == "" {
+		return "Package-" + escapeID(name)
+	}
+	return "Package-" + escapeID(name) + "-" + escapeID(version)
+}
+
+// DependencyEdges returns the edges from a package to its dependencies of
+// a type. Dependencies on other packages point from the dependent, the
+// development, optional and build dependency types read the other way as
+// the dependency is the element of the relationship (X_DEPENDENCY_OF in
+// SPDX), so they get an edge from each dependency.
+func DependencyEdges(edgeType sbom.Edge_Type, dependent string, dependencies []string) []*sbom.Edge {
+	if len(dependencies) == 0 {
+		return []*sbom.Edge{}
+	}
+	switch edgeType {
+	case sbom.Edge_devDependency, sbom.Edge_optionalDependency, sbom.Edge_buildDependency:
+		edges := []*sbom.Edge{}
+		for _, dep := range dependencies {
+			edges = append(edges, &sbom.Edge{Type: edgeType, From: dep, To: []string{dependent}})
+		}
+		return edges
+	default:
+		return []*sbom.Edge{{Type: edgeType, From: dependent, To: dependencies}}
+	}
+}
+
+// FileNode returns a node describing a file in the filesystem
+func FileNode(path string) *sbom.Node {
+	path = "/" + strings.TrimPrefix(path, "/")
+	return &sbom.Node{
+		Id:       FileNodeID(path),
+		Type:     sbom.Node_FILE,
+		Name:     path,
+		Licenses: []string{},
+		Hashes:   map[string]string{},
+	}
+}
+
+// Merge adds the nodes and edges of src to dst. Nodes already in dst are
+// not replaced but the hashes they lack are copied from src. Edges already
+// in dst are not duplicated.
+func Merge(dst, src *sbom.Document) {
+	nodes := map[string]*sbom.Node{}
+	for _, n := range dst.Nodes {
+		nodes[n.Id] = n
+	}
+	for _, n := range src.Nodes {
+		existing, ok := nodes[n.Id]
+		if !ok {
+			dst.Nodes = append(dst.Nodes, n)
+			nodes[n.Id] = n
+			continue
+		}
+		if existing.Hashes == nil {
+			existing.Hashes = map[string]string{}
+		}
+		for algo, value := range n.Hashes {
+			if _, ok := existing.Hashes[algo]; !ok {
+				existing.Hashes[algo] = value
+			}
+		}
+	}
+	edges := map[string]struct{}{}
+	for _, e := range dst.Edges {
+		edges[edgeKey(e)] = struct{}{}
+	}
+	for _, e := range src.Edges {
+		if _, ok := edges[edgeKey(e)]; ok {
+			continue
+		}
+		edges[edgeKey(e)] = struct{}{}
+		dst.Edges = append(dst.Edges, e)
+	}
+}
+
+func edgeKey(e *sbom.Edge) string {
+	return e.Type.String() + " " + e.From + " " + strings.Join(e.To, " ")
+}
+
+// TopLevelPackages returns the IDs of the package nodes that are not
+// contained in other nodes.
+func TopLevelPackages(doc *sbom.Document) []string {
+	contained := map[string]struct{}{}
+	for _, e := range doc.Edges {
+		if e.Type != sbom.Edge_contains {
+			continue
+		}
+		for _, id := range e.To {
+			contained[id] = struct{}{}
+		}
+	}
+	ret := []string{}
+	for _, n := range doc.Nodes {
+		if _, ok := contained[n.Id]; ok || n.Type != sbom.Node_PACKAGE {
+			continue
+		}
+		ret = append(ret, n.Id)
+	}
+	return ret
+}
+
+// OSRelease is the operating system identification from os-release(5)
+type OSRelease struct {
+	ID         string
+	VersionID  string
+	Name       string
+	PrettyName string
+	HomeURL    string
+}
+
+// Distro returns the distribution and version in the form used by the
+// distro qualifier of package URLs, for example "alpine-3.18.2".
+func (r *OSRelease) Distro() string {
+	if r == nil || r.ID == "" {
+		return ""
+	}
+	if r.VersionID == "" {
+		return r.ID
+	}
+	return r.ID + "-" + r.VersionID
+}
+
+// ReadOSRelease reads /etc/os-release, falling back to /usr/lib/os-release.
+// It returns nil without error when neither file exists.
+func ReadOSRelease(fsys fs.FS) (*OSRelease, error) {
+	for _, path := range []string{"etc/os-release", "usr/lib/os-release"} {
+		f, err := fsys.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close()
+
+		r := &OSRelease{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+			if !ok || strings.HasPrefix(key, "#") {
+				continue
+			}
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			} else {
+				value = strings.Trim(value, `'"`)
+			}
+			switch key {
+			case "ID":
+				r.ID = value
+			case "VERSION_ID":
+				r.VersionID = value
+			case "NAME":
+				r.Name = value
+			case "PRETTY_NAME":
+				r.PrettyName = value
+			case "HOME_URL":
+				r.HomeURL = value
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		return r, nil
+	}
+	return nil, nil
+}
+
+// ParsePerson parses a maintainer string in the "Name <email>" form used by
+// the package managers.
+func ParsePerson(s string) *sbom.Person {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	p := &sbom.Person{Name: s}
+	if i := strings.LastIndex(s, "<"); i != -1 && strings.HasSuffix(s, ">") {
+		p.Name = strings.TrimSpace(s[:i])
+		p.Email = strings.TrimSpace(s[i+1 : len(s)-1])
+	}
+	return p
+}
+
+// FromDirectory runs the catalogers on a root filesystem directory and
+// returns a document rooted at a node describing the directory, which
+// contains the packages found. The directory is described as an operating
+// system when it has an os-release file. The files listed in the package databases
+// are hashed, those missing from the directory are dropped.
+func FromDirectory(dir string, catalogers ...Cataloger) (*sbom.Document, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening root filesystem: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	fsys := os.DirFS(dir)
+
+	name := filepath.Base(filepath.Clean(dir))
+	doc := NewDocument()
+	doc.Metadata.Name = name
+	doc.Metadata.Version = "1"
+	doc.Metadata.Date = timestamppb.Now()
+	doc.Metadata.Tools = append(doc.Metadata.Tools, &sbom.Tool{Name: "protobom"})
+
+	root := &sbom.Node{
+		Id:       PackageNodeID(name, ""),
+		Type:     sbom.Node_PACKAGE,
+		Name:     name,
+		Licenses: []string{},
+		Hashes:   map[string]string{},
+	}
+	osRelease, err := ReadOSRelease(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("reading os-release: %w", err)
+	}
+	if osRelease != nil {
+		root.PrimaryPurpose = "OPERATING-SYSTEM"
+		root.Description = osRelease.PrettyName
+		root.UrlHome = osRelease.HomeURL
+	}
+	doc.Nodes = append(doc.Nodes, root)
+	doc.RootElements = append(doc.RootElements, root.Id)
+
+	packages := NewDocument()
+	for _, c := range catalogers {
+		found, err := c.Catalog(fsys)
+		if err != nil {
+			return nil, fmt.Errorf("running %s cataloger: %w", c.Name(), err)
+		}
+		if err := hashFiles(dir, found); err != nil {
+			return nil, fmt.Errorf("hashing files of %s packages: %w", c.Name(), err)
+		}
+		Merge(packages, found)
+	}
+	Merge(doc, packages)
+
+	if top := TopLevelPackages(packages); len(top) > 0 {
+		doc.Edges = append([]*sbom.Edge{{
+			Type: sbom.Edge_contains,
+			From: root.Id,
+			To:   top,
+		}}, doc.Edges...)
+	}
+	return doc, nil
+}
+
+// hashFiles computes the hashes of the file nodes in a cataloger result
+// and removes the files that don't exist in the filesystem.
+func hashFiles(dir string, doc *sbom.Document) error {
+	removed := map[string]struct{}{}
+	nodes := []*sbom.Node{}
+	for _, n := range doc.Nodes {
+		if n.Type != sbom.Node_FILE {
+			nodes = append(nodes, n)
+			continue
+		}
+		hashes, err := fileHashes(filepath.Join(dir, filepath.FromSlash(n.Name)))
+		if errors.Is(err, fs.ErrNotExist) {
+			removed[n.Id] = struct{}{}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if n.Hashes == nil {
+			n.Hashes = map[string]string{}
+		}
+		for algo, value := range hashes {
+			n.Hashes[algo] = value
+		}
+		nodes = append(nodes, n)
+	}
+	doc.Nodes = nodes
+
+	edges := []*sbom.Edge{}
+	for _, e := range doc.Edges {
+		to := []string{}
+		for _, id := range e.To {
+			if _, ok := removed[id]; !ok {
+				to = append(to, id)
+			}
+		}
+		if len(to) == 0 {
+			continue
+		}
+		e.To = to
+		edges = append(edges, e)
+	}
+	doc.Edges = edges
+	return nil
+}
+
+// fileHashes returns the SHA1 and SHA256 of a regular file. Other kinds
+// of files are reported as missing, symlinks are not followed as they may
+// point outside of the root filesystem.
+func fileHashes(name string) (map[string]string, error) {
+	info, err := os.Lstat(name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fs.ErrNotExist
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h1 := sha1.New()
+	h256 := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(h1, h256), f); err != nil {
+		return nil, fmt.Errorf("hashing %s: %w", name, err)
+	}
+	return map[string]string{
+		"SHA1":   hex.EncodeToString(h1.Sum(nil)),
+		"SHA256": hex.EncodeToString(h256.Sum(nil)),
+	}, nil
+}
@@ -0,0 +1,442 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dpkg catalogs the packages in the dpkg database used by Debian
+// and its derivatives.
+package dpkg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+const (
+	// StatusFile is the path of the dpkg status database in a rootfs
+	StatusFile = "var/lib/dpkg/status"
+
+	// InfoDir holds the lists of files installed by each package
+	InfoDir = "var/lib/dpkg/info"
+)
+
+// Package is an installed package in the dpkg status file
+type Package struct {
+	Name         string
+	Version      string
+	Architecture string
+	Status       string
+	Maintainer   string
+	Homepage     string
+	Summary      string
+	Description  string
+
+	// Source is the name of the source package, empty when it has the
+	// same name and version as the binary package. SourceVersion is set
+	// when the source version differs from the binary package version.
+	Source        string
+	SourceVersion string
+
+	// Depends holds the Pre-Depends and Depends of the package. Each entry
+	// lists the alternatives that satisfy the dependency.
+	Depends  [][]string
+	Provides []string
+	Files    []*File
+}
+
+// File is a file installed by a package
+type File struct {
+	Path string
+
+	// MD5 is the checksum recorded in the md5sums of the package
+	MD5 string
+}
+
+// Cataloger reads the dpkg database
+type Cataloger struct{}
+
+// New returns a new dpkg cataloger
+func New() *Cataloger {
+	return &Cataloger{}
+}
+
+// Name returns the name of the cataloger
+func (c *Cataloger) Name() string {
+	return "dpkg"
+}
+
+// Catalog reads the installed packages in the dpkg database of the
+// filesystem. It returns an empty document if there is no database.
+func (c *Cataloger) Catalog(fsys fs.FS) (*sbom.Document, error) {
+	doc := catalog.NewDocument()
+	f, err := fsys.Open(StatusFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening dpkg status: %w", err)
+	}
+	defer f.Close()
+
+	packages, err := ParseStatus(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing dpkg status: %w", err)
+	}
+
+	osRelease, err := catalog.ReadOSRelease(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("reading os-release: %w", err)
+	}
+
+	nodes := map[*Package]*sbom.Node{}
+	for _, p := range packages {
+		if err := readFileList(fsys, p); err != nil {
+			return nil, err
+		}
+		if err := readMD5Sums(fsys, p); err != nil {
+			return nil, err
+		}
+
+		n := packageNode(p, osRelease)
+		nodes[p] = n
+		doc.Nodes = append(doc.Nodes, n)
+
+		files := []string{}
+		for _, pf := range p.Files {
+			fn := catalog.FileNode(pf.Path)
+			if pf.MD5 != "" {
+				fn.Hashes["MD5"] = pf.MD5
+			}
+			doc.Nodes = append(doc.Nodes, fn)
+			files = append(files, fn.Id)
+		}
+		if len(files) > 0 {
+			doc.Edges = append(doc.Edges, &sbom.Edge{
+				Type: sbom.Edge_contains,
+				From: n.Id,
+				To:   files,
+			})
+		}
+	}
+
+	providers := providerIndex(packages)
+	for _, p := range packages {
+		deps := []string{}
+		seen := map[string]struct{}{}
+		for _, alternatives := range p.Depends {
+			provider := resolveDependency(providers, alternatives)
+			if provider == nil || provider == p {
+				continue
+			}
+			id := nodes[provider].Id
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			deps = append(deps, id)
+		}
+		if len(deps) > 0 {
+			doc.Edges = append(doc.Edges, &sbom.Edge{
+				Type: sbom.Edge_dependsOn,
+				From: nodes[p].Id,
+				To:   deps,
+			})
+		}
+	}
+	return doc, nil
+}
+
+// providerIndex maps package names and the virtual packages they provide
+// to the installed packages.
+func providerIndex(packages []*Package) map[string]*Package {
+	providers := map[string]*Package{}
+	for _, p := range packages {
+		for _, name := range p.Provides {
+			if _, ok := providers[name]; !ok {
+				providers[name] = p
+			}
+		}
+	}
+	// Real packages take precedence over the virtual ones
+	for _, p := range packages {
+		providers[p.Name] = p
+	}
+	return providers
+}
+
+// resolveDependency returns the installed package satisfying a dependency,
+// the first of its alternatives that is installed.
+func resolveDependency(providers map[string]*Package, alternatives []string) *Package {
+	for _, name := range alternatives {
+		if p, ok := providers[name]; ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// parseRelations parses a relationship field such as Depends. Relations are
+// separated by commas, alternatives by pipes. Version constraints and
+// architecture qualifiers are dropped, eg "libc6 (>= 2.34), awk | mawk:any"
+// returns [[libc6] [awk mawk]].
+func parseRelations(field string) [][]string {
+	relations := [][]string{}
+	for _, relation := range strings.Split(field, ",") {
+		alternatives := []string{}
+		for _, alt := range strings.Split(relation, "|") {
+			name := strings.TrimSpace(alt)
+			if i := strings.IndexAny(name, " ([<"); i != -1 {
+				name = name[:i]
+			}
+			name, _, _ = strings.Cut(name, ":")
+			if name != "" {
+				alternatives = append(alternatives, name)
+			}
+		}
+		if len(alternatives) > 0 {
+			relations = append(relations, alternatives)
+		}
+	}
+	return relations
+}
+
+// ParseStatus parses the dpkg status file and returns the packages that
+// are installed. Each package is a stanza of "Field: value" lines, fields
+// may continue in lines starting with a space.
+func ParseStatus(r io.Reader) ([]*Package, error) {
+	stanzas, err := parseStanzas(r)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []*Package{}
+	for _, s := range stanzas {
+		p := &Package{
+			Name:         s["Package"],
+			Version:      s["Version"],
+			Architecture: s["Architecture"],
+			Status:       s["Status"],
+			Maintainer:   s["Maintainer"],
+			Homepage:     s["Homepage"],
+			Depends:      [][]string{},
+			Provides:     []string{},
+			Files:        []*File{},
+		}
+		if p.Name == "" || !isInstalled(p.Status) {
+			continue
+		}
+
+		// The first line of the description is the synopsis
+		summary, description, _ := strings.Cut(s["Description"], "\n")
+		p.Summary = summary
+		p.Description = extendedDescription(description)
+
+		// Source may include the version when it differs from the binary
+		// package, eg "glibc (2.36-9)"
+		source, version, _ := strings.Cut(s["Source"], " ")
+		version = strings.Trim(version, "()")
+		if source != "" && (source != p.Name || (version != "" && version != p.Version)) {
+			p.Source = source
+			p.SourceVersion = version
+		}
+
+		p.Depends = append(p.Depends, parseRelations(s["Pre-Depends"])...)
+		p.Depends = append(p.Depends, parseRelations(s["Depends"])...)
+		for _, provides := range parseRelations(s["Provides"]) {
+			p.Provides = append(p.Provides, provides...)
+		}
+		packages = append(packages, p)
+	}
+	return packages, nil
+}
+
+// isInstalled checks the status field (want flag status) of a package
+func isInstalled(status string) bool {
+	if status == "" {
+		return true
+	}
+	fields := strings.Fields(status)
+	return fields[len(fields)-1] == "installed"
+}
+
+// extendedDescription reverts the formatting of the continuation lines of
+// a description, where a single dot marks a blank line.
+func extendedDescription(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l == "." {
+			lines[i] = ""
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// parseStanzas reads a file in the Debian control format
+func parseStanzas(r io.Reader) ([]map[string]string, error) {
+	stanzas := []map[string]string{}
+	var current map[string]string
+	lastKey := ""
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = map[string]string{}
+			stanzas = append(stanzas, current)
+			lastKey = ""
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if lastKey != "" {
+				current[lastKey] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid line in control file: %q", line)
+		}
+		lastKey = key
+		current[key] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading control file: %w", err)
+	}
+	return stanzas, nil
+}
+
+// readFileList reads the files installed by a package from its .list file
+// in the info directory. Multiarch packages use name:arch.list
+func readFileList(fsys fs.FS, p *Package) error {
+	for _, name := range []string{p.Name + ":" + p.Architecture, p.Name} {
+		f, err := fsys.Open(path.Join(InfoDir, name+".list"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("opening file list of %s: %w", p.Name, err)
+		}
+		defer f.Close()
+
+		dirs := map[string]struct{}{}
+		paths := []string{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || line == "/." {
+				continue
+			}
+			// The lists include the directories, which are the parents of
+			// other entries. Only the leaves are recorded as files.
+			dirs[path.Dir(line)] = struct{}{}
+			paths = append(paths, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("reading file list of %s: %w", p.Name, err)
+		}
+		for _, p2 := range paths {
+			if _, ok := dirs[p2]; !ok {
+				p.Files = append(p.Files, &File{Path: p2})
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// readMD5Sums adds the checksums in the md5sums file of a package to its
+// files. The file has "checksum  path" lines with paths relative to the
+// root. Files not listed in the .list file are ignored.
+func readMD5Sums(fsys fs.FS, p *Package) error {
+	for _, name := range []string{p.Name + ":" + p.Architecture, p.Name} {
+		f, err := fsys.Open(path.Join(InfoDir, name+".md5sums"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("opening md5sums of %s: %w", p.Name, err)
+		}
+		defer f.Close()
+
+		sums := map[string]string{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 2 {
+				continue
+			}
+			sums["/"+strings.TrimPrefix(fields[1], "/")] = fields[0]
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("reading md5sums of %s: %w", p.Name, err)
+		}
+		for _, pf := range p.Files {
+			pf.MD5 = sums[pf.Path]
+		}
+		return nil
+	}
+	return nil
+}
+
+// packageNode returns the node of a dpkg package
+func packageNode(p *Package, osRelease *catalog.OSRelease) *sbom.Node {
+	n := &sbom.Node{
+		Id:                 catalog.PackageNodeID(p.Name, p.Version),
+		Type:               sbom.Node_PACKAGE,
+		Name:               p.Name,
+		Version:            p.Version,
+		Summary:            p.Summary,
+		Description:        p.Description,
+		UrlHome:            p.Homepage,
+		Licenses:           []string{},
+		Hashes:             map[string]string{},
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+
+	if maintainer := catalog.ParsePerson(p.Maintainer); maintainer != nil {
+		n.Suppliers = append(n.Suppliers, maintainer)
+	}
+	if p.Source != "" {
+		n.SourceInfo = "built from source package " + p.Source
+		if p.SourceVersion != "" {
+			n.SourceInfo += " " + p.SourceVersion
+		}
+	}
+
+	namespace := "debian"
+	qualifiers := map[string]string{}
+	if p.Architecture != "" {
+		qualifiers["arch"] = p.Architecture
+	}
+	if p.Source != "" {
+		qualifiers["upstream"] = p.Source
+		if p.SourceVersion != "" {
+			qualifiers["upstream"] += "@" + p.SourceVersion
+		}
+	}
+	if osRelease != nil && osRelease.ID != "" {
+		namespace = osRelease.ID
+		if osRelease.VersionID != "" {
+			qualifiers["distro"] = osRelease.Distro()
+		}
+	}
+	n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+		Type:  "purl",
+		Value: purl.New("deb", namespace, p.Name, p.Version, qualifiers).String(),
+	})
+	return n
+}
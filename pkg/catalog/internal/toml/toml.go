@@ -0,0 +1,579 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package toml implements a reader of the subset of TOML used by the
+// lockfiles and manifests of the language package managers.
+//
+// Tables are returned as map[string]interface{} and arrays, including
+// arrays of tables, as []interface{}. Strings and booleans are decoded,
+// integers are returned as int64 and floats as float64. Dates and times
+// are returned as strings.
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parse reads a TOML document
+func Parse(r io.Reader) (map[string]interface{}, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading toml: %w", err)
+	}
+	p := &parser{data: data, line: 1}
+	doc, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	return doc, nil
+}
+
+// Table returns the table under a dotted key path or nil if it does not
+// exist. Arrays of tables resolve to their last element.
+func Table(t map[string]interface{}, path ...string) map[string]interface{} {
+	for _, k := range path {
+		switch v := t[k].(type) {
+		case map[string]interface{}:
+			t = v
+		case []interface{}:
+			if len(v) == 0 {
+				return nil
+			}
+			last, ok := v[len(v)-1].(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			t = last
+		default:
+			return nil
+		}
+	}
+	return t
+}
+
+// Tables returns the tables in an array of tables
+func Tables(t map[string]interface{}, key string) []map[string]interface{} {
+	ret := []map[string]interface{}{}
+	array, ok := t[key].([]interface{})
+	if !ok {
+		return ret
+	}
+	for _, v := range array {
+		if m, ok := v.(map[string]interface{}); ok {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// String returns a string value or an empty string if the key is missing
+// or holds another type.
+func String(t map[string]interface{}, key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+// Bool returns a boolean value, false if missing
+func Bool(t map[string]interface{}, key string) bool {
+	b, _ := t[key].(bool)
+	return b
+}
+
+// Strings returns the strings in an array value
+func Strings(t map[string]interface{}, key string) []string {
+	ret := []string{}
+	array, _ := t[key].([]interface{})
+	for _, v := range array {
+		if s, ok := v.(string); ok {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+type parser struct {
+	data []byte
+	pos  int
+	line int
+}
+
+func (p *parser) parse() (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	current := root
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			array := p.hasPrefix("[[")
+			if array {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			p.skipSpace()
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			if !p.hasPrefix(closing) {
+				return nil, fmt.Errorf("expected %s after table name", closing)
+			}
+			p.pos += len(closing)
+			current, err = openTable(root, keys, array)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if p.eof() || p.peek() != '=' {
+				return nil, fmt.Errorf("expected = after key %s", strings.Join(keys, "."))
+			}
+			p.pos++
+			p.skipSpace()
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if err := setKey(current, keys, value); err != nil {
+				return nil, err
+			}
+		}
+
+		p.skipSpace()
+		p.skipComment()
+		if !p.eof() && p.peek() != '\n' && !p.hasPrefix("\r\n") {
+			return nil, errors.New("expected end of line")
+		}
+	}
+}
+
+// openTable returns the table defined by a header, creating it if needed
+func openTable(root map[string]interface{}, keys []string, array bool) (map[string]interface{}, error) {
+	t := root
+	for i, k := range keys {
+		last := i == len(keys)-1
+		switch v := t[k].(type) {
+		case nil:
+			if last && array {
+				table := map[string]interface{}{}
+				t[k] = []interface{}{table}
+				return table, nil
+			}
+			table := map[string]interface{}{}
+			t[k] = table
+			t = table
+		case map[string]interface{}:
+			if last && array {
+				return nil, fmt.Errorf("%s is not an array of tables", strings.Join(keys, "."))
+			}
+			t = v
+		case []interface{}:
+			if last && array {
+				table := map[string]interface{}{}
+				t[k] = append(v, table)
+				return table, nil
+			}
+			if len(v) == 0 {
+				return nil, fmt.Errorf("%s is an empty array", strings.Join(keys[:i+1], "."))
+			}
+			table, ok := v[len(v)-1].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not an array of tables", strings.Join(keys[:i+1], "."))
+			}
+			t = table
+		default:
+			return nil, fmt.Errorf("%s is not a table", strings.Join(keys[:i+1], "."))
+		}
+	}
+	return t, nil
+}
+
+// setKey assigns a value to a dotted key in a table
+func setKey(t map[string]interface{}, keys []string, value interface{}) error {
+	for _, k := range keys[:len(keys)-1] {
+		switch v := t[k].(type) {
+		case nil:
+			table := map[string]interface{}{}
+			t[k] = table
+			t = table
+		case map[string]interface{}:
+			t = v
+		default:
+			return fmt.Errorf("%s is not a table", k)
+		}
+	}
+	k := keys[len(keys)-1]
+	if _, ok := t[k]; ok {
+		return fmt.Errorf("duplicate key %s", strings.Join(keys, "."))
+	}
+	t[k] = value
+	return nil
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *parser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.data[p.pos:], []byte(s))
+}
+
+// skipSpace skips spaces and tabs
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) skipComment() {
+	if p.eof() || p.peek() != '#' {
+		return
+	}
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments
+func (p *parser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r':
+			p.pos++
+		case '\n':
+			p.pos++
+			p.line++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseKey reads a possibly dotted key
+func (p *parser) parseKey() ([]string, error) {
+	keys := []string{}
+	for {
+		if p.eof() {
+			return nil, errors.New("unexpected end of file in key")
+		}
+		switch c := p.peek(); {
+		case c == '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, s)
+		case c == '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, s)
+		case isBareKeyChar(c):
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			keys = append(keys, string(p.data[start:p.pos]))
+		default:
+			return nil, fmt.Errorf("invalid character %q in key", c)
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+		p.skipSpace()
+	}
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	if p.eof() {
+		return nil, errors.New("missing value")
+	}
+	switch c := p.peek(); c {
+	case '"':
+		if p.hasPrefix(`"""`) {
+			return p.parseMultilineString(`"""`, true)
+		}
+		return p.parseBasicString()
+	case '\'':
+		if p.hasPrefix("'''") {
+			return p.parseMultilineString("'''", false)
+		}
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	default:
+		return p.parseScalar()
+	}
+}
+
+func (p *parser) parseArray() (interface{}, error) {
+	p.pos++
+	array := []interface{}{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, errors.New("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return array, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, v)
+		p.skipBlank()
+		if p.eof() {
+			return nil, errors.New("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("unexpected %q in array", p.peek())
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (interface{}, error) {
+	p.pos++
+	table := map[string]interface{}{}
+	p.skipSpace()
+	if !p.eof() && p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		p.skipSpace()
+		keys, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != '=' {
+			return nil, errors.New("expected = in inline table")
+		}
+		p.pos++
+		p.skipSpace()
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := setKey(table, keys, v); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() {
+			return nil, errors.New("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, fmt.Errorf("unexpected %q in inline table", p.peek())
+		}
+	}
+}
+
+// parseScalar reads booleans, numbers and dates
+func (p *parser) parseScalar() (interface{}, error) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(",]}#\r\n", rune(p.peek())) {
+		// Dates may have a space between the date and the time
+		if p.peek() == ' ' && !(p.pos-start == 10 && p.pos+1 < len(p.data) && p.data[p.pos+1] >= '0' && p.data[p.pos+1] <= '9') {
+			break
+		}
+		p.pos++
+	}
+	token := strings.TrimSpace(string(p.data[start:p.pos]))
+	switch token {
+	case "":
+		return nil, errors.New("missing value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	number := strings.ReplaceAll(token, "_", "")
+	if i, err := strconv.ParseInt(number, 0, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	if token[0] >= '0' && token[0] <= '9' {
+		return token, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", token)
+}
+
+func (p *parser) parseLiteralString() (string, error) {
+	p.pos++
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' {
+		if p.peek() == '\n' {
+			return "", errors.New("newline in string")
+		}
+		p.pos++
+	}
+	if p.eof() {
+		return "", errors.New("unterminated string")
+	}
+	s := string(p.data[start:p.pos])
+	p.pos++
+	return s, nil
+}
+
+func (p *parser) parseBasicString() (string, error) {
+	p.pos++
+	sb := strings.Builder{}
+	for {
+		if p.eof() {
+			return "", errors.New("unterminated string")
+		}
+		switch c := p.peek(); c {
+		case '"':
+			p.pos++
+			return sb.String(), nil
+		case '\n':
+			return "", errors.New("newline in string")
+		case '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseMultilineString reads a string delimited by triple quotes. A newline
+// right after the opening delimiter is trimmed. In basic strings, a
+// backslash at the end of a line trims the following whitespace.
+func (p *parser) parseMultilineString(delim string, escapes bool) (string, error) {
+	p.pos += len(delim)
+	if p.hasPrefix("\r\n") {
+		p.pos += 2
+		p.line++
+	} else if p.hasPrefix("\n") {
+		p.pos++
+		p.line++
+	}
+	sb := strings.Builder{}
+	for {
+		if p.eof() {
+			return "", errors.New("unterminated string")
+		}
+		if p.hasPrefix(delim) {
+			p.pos += len(delim)
+			// Up to two quotes may precede the closing delimiter
+			for i := 0; i < 2 && !p.eof() && p.peek() == delim[0]; i++ {
+				sb.WriteByte(delim[0])
+				p.pos++
+			}
+			return sb.String(), nil
+		}
+		c := p.peek()
+		if c == '\n' {
+			p.line++
+		}
+		if escapes && c == '\\' {
+			rest := p.pos + 1
+			for rest < len(p.data) && (p.data[rest] == ' ' || p.data[rest] == '\t' || p.data[rest] == '\r') {
+				rest++
+			}
+			if rest < len(p.data) && p.data[rest] == '\n' {
+				p.pos = rest
+				p.skipBlank()
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+}
+
+func (p *parser) parseEscape(sb *strings.Builder) error {
+	p.pos++
+	if p.eof() {
+		return errors.New("unterminated escape sequence")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case '"':
+		sb.WriteByte('"')
+	case '\\':
+		sb.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.data) {
+			return errors.New("short unicode escape")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+size]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return fmt.Errorf("invalid unicode escape %q", p.data[p.pos:p.pos+size])
+		}
+		sb.WriteRune(rune(code))
+		p.pos += size
+	default:
+		return fmt.Errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// LockfileParser reads the lockfiles of a language package manager. New
+// ecosystems are supported by implementing a parser and cataloging with
+// NewLockfileCataloger.
+type LockfileParser interface {
+	// Name returns the name of the ecosystem
+	Name() string
+
+	// Match returns true if the file at path is a lockfile of the parser
+	Match(path string) bool
+
+	// Parse reads a lockfile and returns a document with the packages it
+	// locks. When the lockfile identifies the project, its node depends on
+	// the direct dependencies using dependsOn, devDependency and
+	// optionalDependency edges. Parsers can read other files of the
+	// filesystem, such as the project manifest.
+	Parse(fsys fs.FS, path string) (*sbom.Document, error)
+}
+
+// skipDirs are not searched for lockfiles
+var skipDirs = map[string]struct{}{
+	".git":         {},
+	"node_modules": {},
+}
+
+// LockfileCataloger finds the lockfiles of a parser in a filesystem
+type LockfileCataloger struct {
+	parser LockfileParser
+}
+
+// NewLockfileCataloger returns a cataloger reading the lockfiles of parser
+func NewLockfileCataloger(parser LockfileParser) *LockfileCataloger {
+	return &LockfileCataloger{parser: parser}
+}
+
+// Name returns the name of the cataloger
+func (c *LockfileCataloger) Name() string {
+	return c.parser.Name()
+}
+
+// Catalog parses all the lockfiles found in the filesystem and merges
+// their packages in one document.
+func (c *LockfileCataloger) Catalog(fsys fs.FS) (*sbom.Document, error) {
+	paths := []string{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if _, ok := skipDirs[d.Name()]; ok && p != "." {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && c.parser.Match(p) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching %s lockfiles: %w", c.parser.Name(), err)
+	}
+	sort.Strings(paths)
+
+	doc := NewDocument()
+	for _, p := range paths {
+		found, err := c.parser.Parse(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", p, err)
+		}
+		Merge(doc, found)
+	}
+	return doc, nil
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package npm catalogs the packages locked in npm package-lock.json and
+// npm-shrinkwrap.json files.
+package npm
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// Lockfile is a package-lock.json file. Only the packages section of
+// lockfile versions 2 and 3 is read.
+type Lockfile struct {
+	Name            string              `json:"name"`
+	Version         string              `json:"version"`
+	LockfileVersion int                 `json:"lockfileVersion"`
+	Packages        map[string]*Package `json:"packages"`
+}
+
+// Package is an entry in the packages of a lockfile, keyed by the path
+// where it is installed.
+type Package struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Integrity            string            `json:"integrity"`
+	License              interface{}       `json:"license"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// Parser reads npm lockfiles
+type Parser struct{}
+
+// New returns a cataloger of npm lockfiles
+func New() *catalog.LockfileCataloger {
+	return catalog.NewLockfileCataloger(&Parser{})
+}
+
+// Name returns the name of the ecosystem
+func (p *Parser) Name() string {
+	return "npm"
+}
+
+// Match returns true for package-lock.json and npm-shrinkwrap.json files
+func (p *Parser) Match(name string) bool {
+	base := path.Base(name)
+	return base == "package-lock.json" || base == "npm-shrinkwrap.json"
+}
+
+// Parse reads a lockfile. The root package is the project, it depends on
+// its dependencies, devDependencies and optionalDependencies with the
+// matching edge types.
+func (p *Parser) Parse(fsys fs.FS, name string) (*sbom.Document, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading lockfile: %w", err)
+	}
+	lock := &Lockfile{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("decoding lockfile: %w", err)
+	}
+	if lock.Packages == nil {
+		return nil, fmt.Errorf("lockfile version %d is not supported, only versions 2 and 3 list packages", lock.LockfileVersion)
+	}
+	if root, ok := lock.Packages[""]; ok {
+		if root.Name == "" {
+			root.Name = lock.Name
+		}
+		if root.Version == "" {
+			root.Version = lock.Version
+		}
+	}
+
+	doc := catalog.NewDocument()
+	keys := []string{}
+	for key := range lock.Packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nodes := map[string]*sbom.Node{}
+	for _, key := range keys {
+		pkg := lock.Packages[key]
+		if pkg.Link {
+			continue
+		}
+		if pkg.Name == "" {
+			pkg.Name = packageName(key)
+		}
+		if pkg.Name == "" {
+			continue
+		}
+		n := packageNode(pkg)
+		nodes[key] = n
+		doc.Nodes = append(doc.Nodes, n)
+	}
+
+	for _, key := range keys {
+		n, ok := nodes[key]
+		if !ok {
+			continue
+		}
+		pkg := lock.Packages[key]
+		// Peer dependencies are installed by the dependents and are only
+		// recorded when present.
+		for _, deps := range []struct {
+			names    []map[string]string
+			edgeType sbom.Edge_Type
+		}{
+			{[]map[string]string{pkg.Dependencies, pkg.PeerDependencies}, sbom.Edge_dependsOn},
+			{[]map[string]string{pkg.OptionalDependencies}, sbom.Edge_optionalDependency},
+			{[]map[string]string{pkg.DevDependencies}, sbom.Edge_devDependency},
+		} {
+			to := []string{}
+			seen := map[string]struct{}{}
+			for _, dep := range sortedKeys(deps.names...) {
+				target, ok := nodes[resolve(lock, key, dep)]
+				if !ok || target.Id == n.Id {
+					continue
+				}
+				if _, ok := seen[target.Id]; ok {
+					continue
+				}
+				seen[target.Id] = struct{}{}
+				to = append(to, target.Id)
+			}
+			doc.Edges = append(doc.Edges, catalog.DependencyEdges(deps.edgeType, n.Id, to)...)
+		}
+	}
+	return doc, nil
+}
+
+// resolve finds the key of the package that satisfies a dependency using
+// the node module resolution: the node_modules directories of the package
+// and its parents are searched in order. Links resolve to their target.
+func resolve(lock *Lockfile, from, dep string) string {
+	dir := from
+	for {
+		key := "node_modules/" + dep
+		if dir != "" {
+			key = dir + "/" + key
+		}
+		if pkg, ok := lock.Packages[key]; ok {
+			if pkg.Link {
+				return pkg.Resolved
+			}
+			return key
+		}
+		if dir == "" {
+			return ""
+		}
+		i := strings.LastIndex(dir, "node_modules/")
+		if i == -1 {
+			dir = ""
+		} else {
+			dir = strings.TrimSuffix(dir[:i], "/")
+		}
+	}
+}
+
+// packageName returns the package name from its path in the lockfile,
+// eg node_modules/a/node_modules/@scope/b is @scope/b. Workspace packages
+// are named after their directory.
+func packageName(key string) string {
+	if i := strings.LastIndex(key, "node_modules/"); i != -1 {
+		return key[i+len("node_modules/"):]
+	}
+	return path.Base(key)
+}
+
+// sortedKeys returns the names of the dependencies in the maps
+func sortedKeys(maps ...map[string]string) []string {
+	keys := []string{}
+	for _, m := range maps {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// packageNode returns the node of a package in the lockfile
+func packageNode(pkg *Package) *sbom.Node {
+	n := &sbom.Node{
+		Id:                 catalog.PackageNodeID("npm/"+pkg.Name, pkg.Version),
+		Type:               sbom.Node_PACKAGE,
+		Name:               pkg.Name,
+		Version:            pkg.Version,
+		Licenses:           []string{},
+		Hashes:             integrityHashes(pkg.Integrity),
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+	if strings.HasPrefix(pkg.Resolved, "https://") || strings.HasPrefix(pkg.Resolved, "http://") {
+		n.UrlDownload = pkg.Resolved
+	}
+	if license, ok := pkg.License.(string); ok && license != "" {
+		n.Licenses = append(n.Licenses, license)
+	}
+
+	namespace, name := "", pkg.Name
+	if strings.HasPrefix(name, "@") {
+		namespace, name, _ = strings.Cut(name, "/")
+	}
+	n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+		Type:  "purl",
+		Value: purl.New("npm", namespace, name, pkg.Version, nil).String(),
+	})
+	return n
+}
+
+// integrityHashes decodes a subresource integrity string, which lists
+// base64 encoded digests prefixed by their algorithm (eg "sha512-...").
+func integrityHashes(integrity string) map[string]string {
+	hashes := map[string]string{}
+	for _, entry := range strings.Fields(integrity) {
+		algo, digest, ok := strings.Cut(entry, "-")
+		if !ok {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			continue
+		}
+		hashes[strings.ToUpper(algo)] = hex.EncodeToString(data)
+	}
+	return hashes
+}
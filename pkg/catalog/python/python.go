@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package python catalogs the Python packages locked in poetry.lock files
+// and pinned in requirements files.
+package python
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/catalog/internal/toml"
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// Package is a package pinned in a lockfile
+type Package struct {
+	Name        string
+	Version     string
+	Description string
+	URL         string
+
+	// Hashes of the distribution files by algorithm. Poetry records the
+	// hashes of all the wheels and the sdist, the hash of the sdist is
+	// used if present.
+	Hashes map[string]string
+}
+
+// Parser reads poetry lockfiles and requirements files
+type Parser struct{}
+
+// New returns a cataloger of Python lockfiles
+func New() *catalog.LockfileCataloger {
+	return catalog.NewLockfileCataloger(&Parser{})
+}
+
+// Name returns the name of the ecosystem
+func (p *Parser) Name() string {
+	return "pypi"
+}
+
+// Match returns true for poetry.lock and requirements*.txt files
+func (p *Parser) Match(name string) bool {
+	base := path.Base(name)
+	return base == "poetry.lock" ||
+		(strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"))
+}
+
+// Parse reads a lockfile or requirements file
+func (p *Parser) Parse(fsys fs.FS, name string) (*sbom.Document, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening lockfile: %w", err)
+	}
+	defer f.Close()
+
+	if path.Base(name) == "poetry.lock" {
+		lock, err := toml.Parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("decoding poetry lockfile: %w", err)
+		}
+		project, err := readPyProject(fsys, path.Join(path.Dir(name), "pyproject.toml"))
+		if err != nil {
+			return nil, err
+		}
+		return poetryDocument(lock, project), nil
+	}
+
+	packages, err := ParseRequirements(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing requirements: %w", err)
+	}
+	doc := catalog.NewDocument()
+	for _, pkg := range packages {
+		doc.Nodes = append(doc.Nodes, packageNode(pkg))
+	}
+	return doc, nil
+}
+
+// nameSeparators are normalized to dashes in package names (PEP 503)
+var nameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizeName returns the normalized form of a package name
+func NormalizeName(name string) string {
+	return strings.ToLower(nameSeparators.ReplaceAllString(name, "-"))
+}
+
+// requirementName matches the name at the start of a requirement specifier
+var requirementName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+// project is the data of pyproject.toml used to link the project to its
+// direct dependencies, by scope.
+type project struct {
+	name         string
+	version      string
+	dependencies map[sbom.Edge_Type][]string
+}
+
+// readPyProject reads the project name and its direct dependencies from a
+// pyproject.toml. Both the poetry tables and the standard project table
+// are read. It returns nil if the file does not exist.
+func readPyProject(fsys fs.FS, name string) (*project, error) {
+	f, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening pyproject.toml: %w", err)
+	}
+	defer f.Close()
+	data, err := toml.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding pyproject.toml: %w", err)
+	}
+
+	proj := &project{dependencies: map[sbom.Edge_Type][]string{}}
+	add := func(edgeType sbom.Edge_Type, name string) {
+		if name == "" || strings.EqualFold(name, "python") {
+			return
+		}
+		proj.dependencies[edgeType] = append(proj.dependencies[edgeType], NormalizeName(name))
+	}
+
+	if std := toml.Table(data, "project"); std != nil {
+		proj.name = toml.String(std, "name")
+		proj.version = toml.String(std, "version")
+		for _, req := range toml.Strings(std, "dependencies") {
+			add(sbom.Edge_dependsOn, requirementName.FindString(req))
+		}
+		for extra := range toml.Table(std, "optional-dependencies") {
+			for _, req := range toml.Strings(toml.Table(std, "optional-dependencies"), extra) {
+				add(sbom.Edge_optionalDependency, requirementName.FindString(req))
+			}
+		}
+	}
+
+	if poetry := toml.Table(data, "tool", "poetry"); poetry != nil {
+		if proj.name == "" {
+			proj.name = toml.String(poetry, "name")
+			proj.version = toml.String(poetry, "version")
+		}
+		for dep, spec := range toml.Table(poetry, "dependencies") {
+			edgeType := sbom.Edge_dependsOn
+			if t, ok := spec.(map[string]interface{}); ok && toml.Bool(t, "optional") {
+				edgeType = sbom.Edge_optionalDependency
+			}
+			add(edgeType, dep)
+		}
+		for dep := range toml.Table(poetry, "dev-dependencies") {
+			add(sbom.Edge_devDependency, dep)
+		}
+		// Dependency groups other than main are development dependencies
+		for group, value := range toml.Table(poetry, "group") {
+			table, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			edgeType := sbom.Edge_devDependency
+			if group == "main" {
+				edgeType = sbom.Edge_dependsOn
+			}
+			for dep := range toml.Table(table, "dependencies") {
+				add(edgeType, dep)
+			}
+		}
+	}
+
+	if proj.name == "" {
+		return nil, nil
+	}
+	for _, deps := range proj.dependencies {
+		sort.Strings(deps)
+	}
+	return proj, nil
+}
+
+// poetryDocument returns the packages in a poetry lockfile
+func poetryDocument(lock map[string]interface{}, proj *project) *sbom.Document {
+	doc := catalog.NewDocument()
+	nodes := map[string]*sbom.Node{}
+	entries := toml.Tables(lock, "package")
+
+	// Lockfiles before poetry 1.2 list the files in the metadata table
+	legacyFiles := toml.Table(lock, "metadata", "files")
+
+	for _, entry := range entries {
+		pkg := &Package{
+			Name:        toml.String(entry, "name"),
+			Version:     toml.String(entry, "version"),
+			Description: toml.String(entry, "description"),
+			Hashes:      map[string]string{},
+		}
+		if pkg.Name == "" {
+			continue
+		}
+		files, ok := entry["files"].([]interface{})
+		if !ok && legacyFiles != nil {
+			files, _ = legacyFiles[pkg.Name].([]interface{})
+		}
+		pkg.Hashes = distributionHashes(files)
+		if source := toml.Table(entry, "source"); source != nil && toml.String(source, "type") != "legacy" {
+			pkg.URL = toml.String(source, "url")
+		}
+
+		n := packageNode(pkg)
+		nodes[NormalizeName(pkg.Name)] = n
+		doc.Nodes = append(doc.Nodes, n)
+	}
+
+	for _, entry := range entries {
+		from, ok := nodes[NormalizeName(toml.String(entry, "name"))]
+		if !ok {
+			continue
+		}
+		depends := []string{}
+		optional := []string{}
+		deps := toml.Table(entry, "dependencies")
+		names := []string{}
+		for dep := range deps {
+			names = append(names, dep)
+		}
+		sort.Strings(names)
+		for _, dep := range names {
+			to, ok := nodes[NormalizeName(dep)]
+			if !ok {
+				continue
+			}
+			if isOptional(deps[dep]) {
+				optional = append(optional, to.Id)
+			} else {
+				depends = append(depends, to.Id)
+			}
+		}
+		doc.Edges = appendEdge(doc.Edges, sbom.Edge_dependsOn, from.Id, depends)
+		doc.Edges = appendEdge(doc.Edges, sbom.Edge_optionalDependency, from.Id, optional)
+	}
+
+	if proj == nil {
+		return doc
+	}
+	root := packageNode(&Package{Name: proj.name, Version: proj.version, Hashes: map[string]string{}})
+	doc.Nodes = append([]*sbom.Node{root}, doc.Nodes...)
+	for _, edgeType := range []sbom.Edge_Type{sbom.Edge_dependsOn, sbom.Edge_optionalDependency, sbom.Edge_devDependency} {
+		to := []string{}
+		for _, dep := range proj.dependencies[edgeType] {
+			if n, ok := nodes[dep]; ok && n.Id != root.Id {
+				to = append(to, n.Id)
+			}
+		}
+		doc.Edges = appendEdge(doc.Edges, edgeType, root.Id, to)
+	}
+	return doc
+}
+
+// isOptional checks if a dependency in the lockfile is only required by
+// an extra. The specifier is a string, a table or a list of tables with
+// markers.
+func isOptional(spec interface{}) bool {
+	switch v := spec.(type) {
+	case map[string]interface{}:
+		return toml.Bool(v, "optional")
+	case []interface{}:
+		for _, alt := range v {
+			if !isOptional(alt) {
+				return false
+			}
+		}
+		return len(v) > 0
+	}
+	return false
+}
+
+func appendEdge(edges []*sbom.Edge, edgeType sbom.Edge_Type, from string, to []string) []*sbom.Edge {
+	return append(edges, catalog.DependencyEdges(edgeType, from, to)...)
+}
+
+// distributionHashes returns the hashes of the sdist in the files of a
+// package, or of its first file if there is no sdist.
+func distributionHashes(files []interface{}) map[string]string {
+	hashes := map[string]string{}
+	chosen := ""
+	for _, f := range files {
+		entry, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := toml.String(entry, "file")
+		if chosen != "" && !isSdist(name) {
+			continue
+		}
+		algo, digest, ok := strings.Cut(toml.String(entry, "hash"), ":")
+		if !ok {
+			continue
+		}
+		hashes = map[string]string{strings.ToUpper(algo): digest}
+		chosen = name
+		if isSdist(name) {
+			break
+		}
+	}
+	return hashes
+}
+
+func isSdist(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".zip")
+}
+
+// packageNode returns the node of a Python package
+func packageNode(pkg *Package) *sbom.Node {
+	name := NormalizeName(pkg.Name)
+	n := &sbom.Node{
+		Id:                 catalog.PackageNodeID("pypi/"+name, pkg.Version),
+		Type:               sbom.Node_PACKAGE,
+		Name:               pkg.Name,
+		Version:            pkg.Version,
+		Description:        pkg.Description,
+		UrlDownload:        pkg.URL,
+		Licenses:           []string{},
+		Hashes:             pkg.Hashes,
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers: []*sbom.Identifier{{
+			Type:  "purl",
+			Value: purl.New("pypi", "", name, pkg.Version, nil).String(),
+		}},
+	}
+	if n.Hashes == nil {
+		n.Hashes = map[string]string{}
+	}
+	return n
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package python
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseRequirements reads the packages in a requirements file. Only
+// requirements pinned with == have a version, their --hash options are
+// recorded. Options such as -r or --index-url are ignored.
+func ParseRequirements(r io.Reader) ([]*Package, error) {
+	packages := []*Package{}
+	seen := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	add := func(line string) {
+		pkg := parseRequirement(line)
+		if pkg == nil {
+			return
+		}
+		key := NormalizeName(pkg.Name) + "@" + pkg.Version
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		packages = append(packages, pkg)
+	}
+
+	line := ""
+	for scanner.Scan() {
+		text := scanner.Text()
+		// Lines ending in a backslash continue in the next one
+		if strings.HasSuffix(text, "\\") {
+			line += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+		add(line + text)
+		line = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading requirements: %w", err)
+	}
+	add(line)
+	return packages, nil
+}
+
+// parseRequirement parses a requirement line such as
+// "name[extra]==1.0 ; python_version > '3.8' --hash=sha256:..."
+func parseRequirement(line string) *Package {
+	// Comments start with a # preceded by whitespace, URLs may have #egg=
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return nil
+	}
+	if i := strings.Index(line, " #"); i != -1 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "-") {
+		return nil
+	}
+
+	pkg := &Package{Hashes: map[string]string{}}
+	spec := line
+	if i := strings.Index(line, " --"); i != -1 {
+		spec = line[:i]
+		for _, option := range strings.Fields(line[i:]) {
+			value, ok := strings.CutPrefix(option, "--hash=")
+			if !ok {
+				continue
+			}
+			algo, digest, ok := strings.Cut(value, ":")
+			if !ok {
+				continue
+			}
+			if _, ok := pkg.Hashes[strings.ToUpper(algo)]; !ok {
+				pkg.Hashes[strings.ToUpper(algo)] = digest
+			}
+		}
+	}
+	spec, _, _ = strings.Cut(spec, ";")
+
+	pkg.Name = requirementName.FindString(spec)
+	if pkg.Name == "" {
+		return nil
+	}
+	rest := strings.TrimSpace(spec[len(pkg.Name):])
+	if strings.HasPrefix(rest, "[") {
+		if i := strings.Index(rest, "]"); i != -1 {
+			rest = strings.TrimSpace(rest[i+1:])
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "@"):
+		pkg.URL = strings.TrimSpace(strings.TrimPrefix(rest, "@"))
+	case strings.HasPrefix(rest, "==="):
+		pkg.Version = strings.TrimSpace(strings.TrimPrefix(rest, "==="))
+	case strings.HasPrefix(rest, "=="):
+		version := strings.TrimSpace(strings.TrimPrefix(rest, "=="))
+		if !strings.ContainsAny(version, ",*<>!~") {
+			pkg.Version = version
+		}
+	}
+	return pkg
+}
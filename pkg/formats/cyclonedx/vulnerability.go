@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cyclonedx complements the CycloneDX types in onesbom with the
+// parts of the spec protobom needs which are not modeled there, such as the
+// vulnerabilities section used to carry VEX data.
+package cyclonedx
+
+import (
+	"time"
+
+	cdx14 "github.com/onesbom/onesbom/pkg/formats/cyclonedx/v14"
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Document is a CycloneDX 1.4 document including its vulnerabilities
+type Document struct {
+	cdx14.Document
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type Vulnerability struct {
+	Ref            string      `json:"bom-ref,omitempty"`
+	ID             string      `json:"id"`
+	Source         *Source     `json:"source,omitempty"`
+	References     []Reference `json:"references,omitempty"`
+	Ratings        []Rating    `json:"ratings,omitempty"`
+	CWEs           []int32     `json:"cwes,omitempty"`
+	Description    string      `json:"description,omitempty"`
+	Detail         string      `json:"detail,omitempty"`
+	Recommendation string      `json:"recommendation,omitempty"`
+	Advisories     []Advisory  `json:"advisories,omitempty"`
+	Created        *time.Time  `json:"created,omitempty"`
+	Published      *time.Time  `json:"published,omitempty"`
+	Updated        *time.Time  `json:"updated,omitempty"`
+	Analysis       *Analysis   `json:"analysis,omitempty"`
+	Affects        []Affects   `json:"affects,omitempty"`
+}
+
+type Source struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Reference struct {
+	ID     string  `json:"id"`
+	Source *Source `json:"source,omitempty"`
+}
+
+type Rating struct {
+	Source        *Source `json:"source,omitempty"`
+	Score         float64 `json:"score,omitempty"`
+	Severity      string  `json:"severity,omitempty"`
+	Method        string  `json:"method,omitempty"`
+	Vector        string  `json:"vector,omitempty"`
+	Justification string  `json:"justification,omitempty"`
+}
+
+type Advisory struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
+}
+
+type Analysis struct {
+	State         string     `json:"state,omitempty"`
+	Justification string     `json:"justification,omitempty"`
+	Response      []string   `json:"response,omitempty"`
+	Detail        string     `json:"detail,omitempty"`
+	FirstIssued   *time.Time `json:"firstIssued,omitempty"`
+	LastUpdated   *time.Time `json:"lastUpdated,omitempty"`
+}
+
+type Affects struct {
+	Ref      string            `json:"ref"`
+	Versions []AffectedVersion `json:"versions,omitempty"`
+}
+
+type AffectedVersion struct {
+	Version string `json:"version,omitempty"`
+	Range   string `json:"range,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// The CycloneDX spec uses snake case for the VEX enumerations while the
+// protobom enums are camel case, these maps translate between them.
+var (
+	analysisStates = map[string]sbom.Analysis_State{
+		"resolved":               sbom.Analysis_resolved,
+		"resolved_with_pedigree": sbom.Analysis_resolvedWithPedigree,
+		"exploitable":            sbom.Analysis_exploitable,
+		"in_triage":              sbom.Analysis_inTriage,
+		"false_positive":         sbom.Analysis_falsePositive,
+		"not_affected":           sbom.Analysis_notAffected,
+	}
+
+	analysisJustifications = map[string]sbom.Analysis_Justification{
+		"code_not_present":                sbom.Analysis_codeNotPresent,
+		"code_not_reachable":              sbom.Analysis_codeNotReachable,
+		"requires_configuration":          sbom.Analysis_requiresConfiguration,
+		"requires_dependency":             sbom.Analysis_requiresDependency,
+		"requires_environment":            sbom.Analysis_requiresEnvironment,
+		"protected_by_compiler":           sbom.Analysis_protectedByCompiler,
+		"protected_at_runtime":            sbom.Analysis_protectedAtRuntime,
+		"protected_at_perimeter":          sbom.Analysis_protectedAtPerimeter,
+		"protected_by_mitigating_control": sbom.Analysis_protectedByMitigatingControl,
+	}
+
+	analysisResponses = map[string]sbom.Analysis_Response{
+		"can_not_fix":          sbom.Analysis_canNotFix,
+		"will_not_fix":         sbom.Analysis_willNotFix,
+		"update":               sbom.Analysis_update,
+		"rollback":             sbom.Analysis_rollback,
+		"workaround_available": sbom.Analysis_workaroundAvailable,
+	}
+)
+
+// VulnerabilityToProto converts a CycloneDX vulnerability to protobom
+func VulnerabilityToProto(v *Vulnerability) *sbom.Vulnerability {
+	pv := &sbom.Vulnerability{
+		Id:             v.ID,
+		Aliases:        []string{},
+		Ratings:        []*sbom.Rating{},
+		Cwes:           v.CWEs,
+		Description:    v.Description,
+		Detail:         v.Detail,
+		Recommendation: v.Recommendation,
+		Advisories:     []*sbom.ExternalReference{},
+		Created:        timeToProto(v.Created),
+		Published:      timeToProto(v.Published),
+		Updated:        timeToProto(v.Updated),
+		Affects:        []*sbom.Affects{},
+	}
+
+	if v.Source != nil {
+		pv.SourceName = v.Source.Name
+		pv.SourceUrl = v.Source.URL
+	}
+
+	for _, r := range v.References {
+		pv.Aliases = append(pv.Aliases, r.ID)
+	}
+
+	for _, r := range v.Ratings {
+		rating := &sbom.Rating{
+			Score:         r.Score,
+			Severity:      sbom.Rating_Severity(sbom.Rating_Severity_value[r.Severity]),
+			Method:        r.Method,
+			Vector:        r.Vector,
+			Justification: r.Justification,
+		}
+		if r.Source != nil {
+			rating.SourceName = r.Source.Name
+			rating.SourceUrl = r.Source.URL
+		}
+		pv.Ratings = append(pv.Ratings, rating)
+	}
+
+	for _, a := range v.Advisories {
+		pv.Advisories = append(pv.Advisories, &sbom.ExternalReference{
+			Url:     a.URL,
+			Type:    "advisory",
+			Comment: a.Title,
+		})
+	}
+
+	if v.Analysis != nil {
+		pv.Analysis = &sbom.Analysis{
+			State:         analysisStates[v.Analysis.State],
+			Justification: analysisJustifications[v.Analysis.Justification],
+			Response:      []sbom.Analysis_Response{},
+			Detail:        v.Analysis.Detail,
+			FirstIssued:   timeToProto(v.Analysis.FirstIssued),
+			LastUpdated:   timeToProto(v.Analysis.LastUpdated),
+		}
+		for _, r := range v.Analysis.Response {
+			pv.Analysis.Response = append(pv.Analysis.Response, analysisResponses[r])
+		}
+	}
+
+	for _, a := range v.Affects {
+		affects := &sbom.Affects{
+			NodeId:   a.Ref,
+			Versions: []*sbom.AffectedVersion{},
+		}
+		for _, av := range a.Versions {
+			affects.Versions = append(affects.Versions, &sbom.AffectedVersion{
+				Version: av.Version,
+				Range:   av.Range,
+				Status:  sbom.AffectedVersion_Status(sbom.AffectedVersion_Status_value[av.Status]),
+			})
+		}
+		pv.Affects = append(pv.Affects, affects)
+	}
+
+	return pv
+}
+
+// VulnerabilityFromProto converts a protobom vulnerability to CycloneDX
+func VulnerabilityFromProto(pv *sbom.Vulnerability) *Vulnerability {
+	v := &Vulnerability{
+		ID:             pv.Id,
+		CWEs:           pv.Cwes,
+		Description:    pv.Description,
+		Detail:         pv.Detail,
+		Recommendation: pv.Recommendation,
+		Created:        timeFromProto(pv.Created),
+		Published:      timeFromProto(pv.Published),
+		Updated:        timeFromProto(pv.Updated),
+	}
+
+	if pv.SourceName != "" || pv.SourceUrl != "" {
+		v.Source = &Source{Name: pv.SourceName, URL: pv.SourceUrl}
+	}
+
+	for _, alias := range pv.Aliases {
+		v.References = append(v.References, Reference{ID: alias})
+	}
+
+	for _, r := range pv.Ratings {
+		rating := Rating{
+			Score:         r.Score,
+			Method:        r.Method,
+			Vector:        r.Vector,
+			Justification: r.Justification,
+		}
+		if r.Severity != sbom.Rating_UNKNOWN {
+			rating.Severity = r.Severity.String()
+		}
+		if r.SourceName != "" || r.SourceUrl != "" {
+			rating.Source = &Source{Name: r.SourceName, URL: r.SourceUrl}
+		}
+		v.Ratings = append(v.Ratings, rating)
+	}
+
+	for _, a := range pv.Advisories {
+		v.Advisories = append(v.Advisories, Advisory{Title: a.Comment, URL: a.Url})
+	}
+
+	if pv.Analysis != nil {
+		v.Analysis = &Analysis{
+			Detail:      pv.Analysis.Detail,
+			FirstIssued: timeFromProto(pv.Analysis.FirstIssued),
+			LastUpdated: timeFromProto(pv.Analysis.LastUpdated),
+		}
+		for k, s := range analysisStates {
+			if s == pv.Analysis.State {
+				v.Analysis.State = k
+			}
+		}
+		for k, j := range analysisJustifications {
+			if j == pv.Analysis.Justification {
+				v.Analysis.Justification = k
+			}
+		}
+		// CycloneDX has no justification for components not present
+		if pv.Analysis.Justification == sbom.Analysis_componentNotPresent {
+			v.Analysis.Justification = "code_not_present"
+		}
+		for _, r := range pv.Analysis.Response {
+			for k, rv := range analysisResponses {
+				if rv == r {
+					v.Analysis.Response = append(v.Analysis.Response, k)
+				}
+			}
+		}
+	}
+
+	for _, a := range pv.Affects {
+		affects := Affects{Ref: a.NodeId}
+		for _, av := range a.Versions {
+			status := ""
+			if av.Status != sbom.AffectedVersion_UNKNOWN {
+				status = av.Status.String()
+			}
+			affects.Versions = append(affects.Versions, AffectedVersion{
+				Version: av.Version,
+				Range:   av.Range,
+				Status:  status,
+			})
+		}
+		v.Affects = append(v.Affects, affects)
+	}
+
+	return v
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
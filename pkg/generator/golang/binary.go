@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package golang
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// IdentifierVCSRevision is the identifier type of the version control
+// revision the main module of a binary was built from
+const IdentifierVCSRevision = "vcs.revision"
+
+// executableFormats maps the magic numbers of the executable formats
+// supported by debug/buildinfo to their names.
+var executableFormats = []struct {
+	magic []byte
+	name  string
+}{
+	{[]byte("\x7fELF"), "ELF"},
+	{[]byte("MZ"), "PE"},
+	{[]byte("\xfe\xed\xfa\xce"), "Mach-O"},
+	{[]byte("\xfe\xed\xfa\xcf"), "Mach-O"},
+	{[]byte("\xce\xfa\xed\xfe"), "Mach-O"},
+	{[]byte("\xcf\xfa\xed\xfe"), "Mach-O"},
+	{[]byte("\xca\xfe\xba\xbe"), "Mach-O"},
+}
+
+// FromBinary generates an SBOM of a Go executable from the build
+// information embedded by the toolchain. The binary is the root of the
+// document, it links statically all the modules listed in the build info
+// and depends on the main module. The main module depends on the rest of
+// the modules, binaries don't record the full module graph. The build
+// settings are recorded as properties of the binary node.
+func FromBinary(path string) (*sbom.Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening binary: %w", err)
+	}
+	defer f.Close()
+
+	format, err := executableFormat(f)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := buildinfo.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading build info from %s: %w", path, err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("rewinding binary: %w", err)
+	}
+	hashes, err := fileHashes(f)
+	if err != nil {
+		return nil, fmt.Errorf("hashing binary: %w", err)
+	}
+
+	name := filepath.Base(path)
+	doc := newDocument(name)
+
+	binNode := &sbom.Node{
+		Id:          nodeID("File", name),
+		Type:        sbom.Node_FILE,
+		Name:        name,
+		FileTypes:   []string{"BINARY", "APPLICATION"},
+		Description: fmt.Sprintf("%s executable built with %s", format, info.GoVersion),
+		Licenses:    []string{},
+		Hashes:      hashes,
+		Properties:  buildSettings(info),
+	}
+	doc.Nodes = append(doc.Nodes, binNode)
+	doc.RootElements = append(doc.RootElements, binNode.Id)
+
+	mainNode := moduleNode(buildInfoModule(&info.Main), moduleSum(&info.Main))
+	mainNode.PrimaryPurpose = "APPLICATION"
+	mainNode.SourceInfo = vcsInfo(info)
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && s.Value != "" {
+			mainNode.Identifiers = append(mainNode.Identifiers, &sbom.Identifier{
+				Type:  IdentifierVCSRevision,
+				Value: s.Value,
+			})
+		}
+	}
+	doc.Nodes = append(doc.Nodes, mainNode)
+
+	linked := []string{mainNode.Id}
+	deps := []string{}
+
+	if stdlib := stdlibNode(info.GoVersion); stdlib != nil {
+		doc.Nodes = append(doc.Nodes, stdlib)
+		linked = append(linked, stdlib.Id)
+		deps = append(deps, stdlib.Id)
+	}
+
+	for _, dep := range info.Deps {
+		n := moduleNode(buildInfoModule(dep), moduleSum(dep))
+		doc.Nodes = append(doc.Nodes, n)
+		linked = append(linked, n.Id)
+		deps = append(deps, n.Id)
+	}
+
+	doc.Edges = append(doc.Edges, &sbom.Edge{
+		Type: sbom.Edge_staticLink,
+		From: binNode.Id,
+		To:   linked,
+	}, &sbom.Edge{
+		Type: sbom.Edge_dependsOn,
+		From: binNode.Id,
+		To:   []string{mainNode.Id},
+	})
+	if len(deps) > 0 {
+		doc.Edges = append(doc.Edges, &sbom.Edge{
+			Type: sbom.Edge_dependsOn,
+			From: mainNode.Id,
+			To:   deps,
+		})
+	}
+	return doc, nil
+}
+
+// executableFormat checks the magic number of the file and returns the
+// name of the executable format.
+func executableFormat(r io.ReadSeeker) (string, error) {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return "", fmt.Errorf("reading executable header: %w", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewinding binary: %w", err)
+	}
+	for _, f := range executableFormats {
+		if bytes.HasPrefix(magic, f.magic) {
+			return f.name, nil
+		}
+	}
+	return "", fmt.Errorf("file is not an ELF, Mach-O or PE executable")
+}
+
+// buildInfoModule converts a module from the build info
+func buildInfoModule(m *debug.Module) *Module {
+	ret := &Module{Path: m.Path, Version: m.Version}
+	if ret.Version == "(devel)" {
+		ret.Version = ""
+	}
+	if m.Replace != nil {
+		ret.Replace = &Module{Path: m.Replace.Path, Version: m.Replace.Version}
+	}
+	return ret
+}
+
+// moduleSum returns the h1: hash of the code that was built, which is the
+// replacement's when the module is replaced.
+func moduleSum(m *debug.Module) string {
+	if m.Replace != nil {
+		return m.Replace.Sum
+	}
+	return m.Sum
+}
+
+// stdlibNode returns a node for the standard library of the toolchain that
+// built the binary. OSV databases publish Go vulnerabilities in the
+// standard library under the stdlib package.
+func stdlibNode(goVersion string) *sbom.Node {
+	version, _, _ := strings.Cut(strings.TrimPrefix(goVersion, "go"), " ")
+	if version == "" {
+		return nil
+	}
+	return &sbom.Node{
+		Id:             nodeID("Package", "golang", "stdlib", version),
+		Type:           sbom.Node_PACKAGE,
+		Name:           "stdlib",
+		Version:        version,
+		PrimaryPurpose: "LIBRARY",
+		Licenses:       []string{"BSD-3-Clause"},
+		Hashes:         map[string]string{},
+		Identifiers: []*sbom.Identifier{{
+			Type:  "purl",
+			Value: purl.New("golang", "", "stdlib", version, nil).String(),
+		}},
+	}
+}
+
+// buildSettings returns the build settings recorded in the binary as
+// properties named after the setting keys (GOOS, CGO_ENABLED, vcs.time...)
+func buildSettings(info *buildinfo.BuildInfo) []*sbom.Property {
+	props := []*sbom.Property{}
+	for _, s := range info.Settings {
+		props = append(props, &sbom.Property{Name: s.Key, Value: s.Value})
+	}
+	return props
+}
+
+// vcsInfo describes the version control revision the binary was built from
+func vcsInfo(info *buildinfo.BuildInfo) string {
+	settings := map[string]string{}
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+	if settings["vcs.revision"] == "" {
+		return ""
+	}
+	ret := fmt.Sprintf("built from %s revision %s", settings["vcs"], settings["vcs.revision"])
+	if settings["vcs.time"] != "" {
+		ret += " committed at " + settings["vcs.time"]
+	}
+	if settings["vcs.modified"] == "true" {
+		ret += " with local modifications"
+	}
+	return ret
+}
+
+// fileHashes computes the SHA1 and SHA256 digests of a file
+func fileHashes(r io.Reader) (map[string]string, error) {
+	h1 := sha1.New()
+	h256 := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(h1, h256), r); err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"SHA1":   hex.EncodeToString(h1.Sum(nil)),
+		"SHA256": hex.EncodeToString(h256.Sum(nil)),
+	}, nil
+}
@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package golang
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Module is a module version as listed in go.mod, go.sum or modules.txt
+type Module struct {
+	Path     string
+	Version  string
+	Indirect bool
+
+	// Replace is the module replacing this one, if any. Local directory
+	// replacements have no version.
+	Replace *Module
+}
+
+// ModFile is the data read from a go.mod file
+type ModFile struct {
+	Module  string
+	Go      string
+	Require []*Module
+	Replace map[string]*Module
+}
+
+// replacement returns the replacement of a module version, replace
+// directives without a version apply to all versions of a module.
+func (mf *ModFile) replacement(path, version string) *Module {
+	if r, ok := mf.Replace[path+"@"+version]; ok {
+		return r
+	}
+	return mf.Replace[path]
+}
+
+// ParseModFile reads the directives in a go.mod file used to build the
+// module graph: module, go, require and replace.
+func ParseModFile(r io.Reader) (*ModFile, error) {
+	mf := &ModFile{
+		Require: []*Module{},
+		Replace: map[string]*Module{},
+	}
+
+	block := ""
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		fields, err := modFields(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			fields = append([]string{block}, fields...)
+		} else if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: invalid module directive", lineNumber)
+			}
+			mf.Module = fields[1]
+		case "go":
+			if len(fields) == 2 {
+				mf.Go = fields[1]
+			}
+		case "require":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: invalid require directive", lineNumber)
+			}
+			mf.Require = append(mf.Require, &Module{
+				Path:     fields[1],
+				Version:  fields[2],
+				Indirect: strings.TrimSpace(comment) == "indirect" || strings.HasPrefix(strings.TrimSpace(comment), "indirect;"),
+			})
+		case "replace":
+			old, repl, err := parseReplace(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			key := old.Path
+			if old.Version != "" {
+				key += "@" + old.Version
+			}
+			mf.Replace[key] = repl
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading go.mod: %w", err)
+	}
+	if mf.Module == "" {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+	return mf, nil
+}
+
+// parseReplace parses the arguments of a replace directive:
+// old [version] => new [version]
+func parseReplace(fields []string) (old, repl *Module, err error) {
+	arrow := -1
+	for i, f := range fields {
+		if f == "=>" {
+			arrow = i
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(fields)-arrow < 2 || len(fields)-arrow > 3 {
+		return nil, nil, fmt.Errorf("invalid replace directive")
+	}
+
+	old = &Module{Path: fields[0]}
+	if arrow == 2 {
+		old.Version = fields[1]
+	}
+	repl = &Module{Path: fields[arrow+1]}
+	if len(fields)-arrow == 3 {
+		repl.Version = fields[arrow+2]
+	}
+	return old, repl, nil
+}
+
+// modFields splits a go.mod line in its tokens, unquoting quoted strings
+func modFields(line string) ([]string, error) {
+	fields := []string{}
+	line = strings.TrimSpace(line)
+	for line != "" {
+		var token string
+		if line[0] == '"' || line[0] == '`' {
+			end := strings.IndexByte(line[1:], line[0])
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			s, err := strconv.Unquote(line[:end+2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted string: %w", err)
+			}
+			token = s
+			line = line[end+2:]
+		} else {
+			end := strings.IndexAny(line, " \t")
+			if end == -1 {
+				end = len(line)
+			}
+			token = line[:end]
+			line = line[end:]
+		}
+		fields = append(fields, token)
+		line = strings.TrimSpace(line)
+	}
+	return fields, nil
+}
+
+// ParseSumFile reads a go.sum file and returns the h1: hashes of the module
+// contents indexed by path@version. The hashes of the go.mod files are
+// not returned.
+func ParseSumFile(r io.Reader) (map[string]string, error) {
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid go.sum line: %q", scanner.Text())
+		}
+		if strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		sums[fields[0]+"@"+fields[1]] = fields[2]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading go.sum: %w", err)
+	}
+	return sums, nil
+}
+
+// ParseVendorModules reads the modules listed in vendor/modules.txt. Modules
+// marked as explicit are required directly in go.mod.
+func ParseVendorModules(r io.Reader) ([]*Module, error) {
+	modules := []*Module{}
+	var current *Module
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "## "):
+			if current == nil {
+				continue
+			}
+			for _, annotation := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+				if strings.TrimSpace(annotation) == "explicit" {
+					current.Indirect = false
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# "))
+			m, err := parseVendorModule(fields)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %q: %w", line, err)
+			}
+			current = m
+			modules = append(modules, m)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading modules.txt: %w", err)
+	}
+	return modules, nil
+}
+
+// parseVendorModule parses a module line in modules.txt:
+// path version [=> replacement [version]] or path => replacement [version]
+func parseVendorModule(fields []string) (*Module, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty module line")
+	}
+	m := &Module{Path: fields[0], Indirect: true}
+	rest := fields[1:]
+	if len(rest) > 0 && rest[0] != "=>" {
+		m.Version = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return m, nil
+	}
+	if rest[0] != "=>" || len(rest) < 2 || len(rest) > 3 {
+		return nil, fmt.Errorf("invalid replacement")
+	}
+	m.Replace = &Module{Path: rest[1]}
+	if len(rest) == 3 {
+		m.Replace.Version = rest[2]
+	}
+	return m, nil
+}
@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package golang generates SBOMs of Go modules and binaries
+package golang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// IdentifierGoSum is the identifier type of the h1: hash that go.sum and the
+// build info of binaries record for a module. The SPDX writer emits it as an
+// external reference of the OTHER category, CycloneDX has no place for it.
+const IdentifierGoSum = "go.sum"
+
+// ModuleOptions controls the generation of SBOMs from module sources
+type ModuleOptions struct {
+	// ModCache is the module cache used to read the go.mod files of the
+	// dependencies to reconstruct the module graph. It defaults to
+	// $GOMODCACHE or $GOPATH/pkg/mod. When the go.mod of a dependency is
+	// not in the cache, the dependency is linked to the main module.
+	ModCache string
+
+	// IgnoreVendor skips vendor/modules.txt even if it exists
+	IgnoreVendor bool
+}
+
+// FromModule generates an SBOM of the module in dir. The module list is
+// read from vendor/modules.txt when the module is vendored or from go.mod
+// otherwise, hashes come from go.sum.
+func FromModule(dir string, opts *ModuleOptions) (*sbom.Document, error) {
+	if opts == nil {
+		opts = &ModuleOptions{}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("opening go.mod: %w", err)
+	}
+	defer f.Close()
+	modFile, err := ParseModFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	sums := map[string]string{}
+	if f, err := os.Open(filepath.Join(dir, "go.sum")); err == nil {
+		defer f.Close()
+		if sums, err = ParseSumFile(f); err != nil {
+			return nil, fmt.Errorf("parsing go.sum: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("opening go.sum: %w", err)
+	}
+
+	modules := []*Module{}
+	for _, m := range modFile.Require {
+		m.Replace = modFile.replacement(m.Path, m.Version)
+		modules = append(modules, m)
+	}
+
+	if !opts.IgnoreVendor {
+		f, err := os.Open(filepath.Join(dir, "vendor", "modules.txt"))
+		if err == nil {
+			defer f.Close()
+			if modules, err = ParseVendorModules(f); err != nil {
+				return nil, fmt.Errorf("parsing vendor/modules.txt: %w", err)
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("opening vendor/modules.txt: %w", err)
+		}
+	}
+
+	doc := newDocument(modFile.Module)
+	mainNode := moduleNode(&Module{Path: modFile.Module}, "")
+	mainNode.PrimaryPurpose = "APPLICATION"
+	doc.Nodes = append(doc.Nodes, mainNode)
+	doc.RootElements = append(doc.RootElements, mainNode.Id)
+
+	// Index the selected version of each module by path
+	selected := map[string]*sbom.Node{}
+	for _, m := range modules {
+		eff := effectiveModule(m)
+		n := moduleNode(m, sums[eff.Path+"@"+eff.Version])
+		doc.Nodes = append(doc.Nodes, n)
+		selected[m.Path] = n
+	}
+
+	direct := []string{}
+	for _, m := range modules {
+		if !m.Indirect {
+			direct = append(direct, selected[m.Path].Id)
+		}
+	}
+	if len(direct) > 0 {
+		doc.Edges = append(doc.Edges, &sbom.Edge{
+			Type: sbom.Edge_dependsOn,
+			From: mainNode.Id,
+			To:   direct,
+		})
+	}
+
+	// Rebuild the rest of the graph from the go.mod files of the dependencies
+	modCache := opts.ModCache
+	if modCache == "" {
+		modCache = defaultModCache()
+	}
+	for _, m := range modules {
+		reqs, err := dependencyRequirements(dir, modCache, m)
+		if err != nil {
+			return nil, fmt.Errorf("reading requirements of %s: %w", m.Path, err)
+		}
+		to := []string{}
+		for _, req := range reqs {
+			if n, ok := selected[req]; ok && req != m.Path {
+				to = append(to, n.Id)
+			}
+		}
+		if len(to) > 0 {
+			doc.Edges = append(doc.Edges, &sbom.Edge{
+				Type: sbom.Edge_dependsOn,
+				From: selected[m.Path].Id,
+				To:   to,
+			})
+		}
+	}
+
+	linkOrphans(doc, mainNode.Id)
+	return doc, nil
+}
+
+// effectiveModule returns the module that provides the code of m, which is
+// the replacement when there is one.
+func effectiveModule(m *Module) *Module {
+	if m.Replace != nil && m.Replace.Version != "" {
+		return m.Replace
+	}
+	return m
+}
+
+// dependencyRequirements returns the paths of the modules required by a
+// dependency, read from its go.mod in the module cache or, for directory
+// replacements, from disk. Missing go.mod files return no requirements.
+func dependencyRequirements(dir, modCache string, m *Module) ([]string, error) {
+	var path string
+	eff := effectiveModule(m)
+	switch {
+	case m.Replace != nil && m.Replace.Version == "":
+		path = m.Replace.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Join(path, "go.mod")
+	case modCache != "" && eff.Version != "":
+		escPath, err := escapeModulePath(eff.Path)
+		if err != nil {
+			return nil, err
+		}
+		escVersion, err := escapeModulePath(eff.Version)
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(modCache, "cache", "download", filepath.FromSlash(escPath), "@v", escVersion+".mod")
+	default:
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening go.mod: %w", err)
+	}
+	defer f.Close()
+
+	mf, err := ParseModFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	ret := []string{}
+	for _, req := range mf.Require {
+		ret = append(ret, req.Path)
+	}
+	return ret, nil
+}
+
+// defaultModCache returns the location of the module cache as the go
+// command computes it.
+func defaultModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// escapeModulePath escapes a module path or version for the module cache,
+// upper case letters are replaced by an exclamation mark and the lower case
+// letter.
+func escapeModulePath(s string) (string, error) {
+	sb := strings.Builder{}
+	for _, r := range s {
+		switch {
+		case r == '!' || r >= unicode.MaxASCII:
+			return "", fmt.Errorf("invalid character %q in %q", r, s)
+		case unicode.IsUpper(r):
+			sb.WriteByte('!')
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// newDocument returns an empty document describing a Go module
+func newDocument(name string) *sbom.Document {
+	return &sbom.Document{
+		Metadata: &sbom.Metadata{
+			Name:    name,
+			Version: "1",
+			Date:    timestamppb.Now(),
+			Tools:   []*sbom.Tool{{Name: "protobom"}},
+			Authors: []*sbom.Person{},
+		},
+		RootElements: []string{},
+		Nodes:        []*sbom.Node{},
+		Edges:        []*sbom.Edge{},
+	}
+}
+
+// moduleNode returns the node of a module. The h1: hash from go.sum is a
+// hash of the module's file list, not a checksum of any artifact, so it is
+// recorded as an identifier instead of in the node hashes.
+func moduleNode(m *Module, h1 string) *sbom.Node {
+	eff := effectiveModule(m)
+	version := eff.Version
+	if m.Replace != nil && m.Replace.Version == "" {
+		version = m.Version
+	}
+
+	n := &sbom.Node{
+		Id:                 nodeID("Package", "golang", m.Path, version),
+		Type:               sbom.Node_PACKAGE,
+		Name:               m.Path,
+		Version:            version,
+		PrimaryPurpose:     "LIBRARY",
+		Licenses:           []string{},
+		Hashes:             map[string]string{},
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+
+	if m.Replace != nil {
+		n.Comment = strings.TrimSpace(fmt.Sprintf("replaced by %s %s", m.Replace.Path, m.Replace.Version))
+	}
+	if strings.HasPrefix(h1, "h1:") {
+		n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+			Type:  IdentifierGoSum,
+			Value: h1,
+		})
+	}
+
+	// Directory replacements are not published, they don't get a purl
+	if m.Replace != nil && m.Replace.Version == "" {
+		return n
+	}
+
+	namespace, name := "", eff.Path
+	if i := strings.LastIndex(eff.Path, "/"); i != -1 {
+		namespace, name = eff.Path[:i], eff.Path[i+1:]
+	}
+	n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+		Type:  "purl",
+		Value: purl.New("golang", namespace, name, eff.Version, nil).String(),
+	})
+
+	if eff.Version != "" {
+		if escPath, err := escapeModulePath(eff.Path); err == nil {
+			if escVersion, err := escapeModulePath(eff.Version); err == nil {
+				n.UrlDownload = fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.zip", escPath, escVersion)
+			}
+		}
+	}
+	return n
+}
+
+// nodeID joins the non empty parts of an ID replacing the characters not
+// allowed in SPDX identifiers.
+func nodeID(parts ...string) string {
+	nonEmpty := []string{}
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-') {
+			return '-'
+		}
+		return r
+	}, strings.Join(nonEmpty, "-"))
+}
+
+// linkOrphans adds a dependsOn edge from the root to the nodes that can't
+// be reached from it, which happens when the module graph is incomplete.
+func linkOrphans(doc *sbom.Document, rootID string) {
+	paths := doc.RootPaths()
+	orphans := []string{}
+	for _, n := range doc.Nodes {
+		if n.Id == rootID {
+			continue
+		}
+		if path := paths.PathTo(n.Id); len(path) < 2 {
+			orphans = append(orphans, n.Id)
+		}
+	}
+	if len(orphans) == 0 {
+		return
+	}
+	for _, e := range doc.Edges {
+		if e.From == rootID && e.Type == sbom.Edge_dependsOn {
+			e.To = append(e.To, orphans...)
+			return
+		}
+	}
+	doc.Edges = append(doc.Edges, &sbom.Edge{
+		Type: sbom.Edge_dependsOn,
+		From: rootID,
+		To:   orphans,
+	})
+}
@@ -0,0 +1,402 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oci generates SBOMs of container images stored in OCI layouts or
+// docker save tarballs.
+package oci
+
+import (
+	"archive/tar"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/catalog"
+	"github.com/puerco/protobom/pkg/catalog/apk"
+	"github.com/puerco/protobom/pkg/catalog/dpkg"
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// metadataPaths are the files kept in memory while reading the layers so
+// that the catalogers can read them.
+var metadataPaths = []string{
+	"etc/os-release",
+	"usr/lib/os-release",
+	"lib/apk/db/",
+	"var/lib/dpkg/",
+}
+
+// maxMetadataSize is the largest metadata file kept in memory
+const maxMetadataSize = 64 << 20
+
+// Options controls the generation of image SBOMs
+type Options struct {
+	// Name is the reference of the image, its package URL takes the name,
+	// repository and tag from it. Defaults to the reference recorded in the
+	// layout.
+	Name string
+
+	// Platform selects the image of a multi architecture index, in the
+	// os/arch[/variant] form. Defaults to the first image.
+	Platform string
+
+	// Catalogers detect the packages in the image filesystem. Defaults to
+	// DefaultCatalogers.
+	Catalogers []catalog.Cataloger
+}
+
+// DefaultCatalogers returns the catalogers used to find OS packages
+func DefaultCatalogers() []catalog.Cataloger {
+	return []catalog.Cataloger{apk.New(), dpkg.New()}
+}
+
+// imageFile is a regular file in the image filesystem
+type imageFile struct {
+	hashes map[string]string
+	layer  int
+}
+
+// FromImage generates an SBOM of an image from an OCI layout directory or
+// a docker save tarball. The image is the root of the document. It
+// contains the OS packages detected, which in turn contain the files they
+// own. Files not owned by any package are contained by the image.
+func FromImage(imagePath string, opts *Options) (*sbom.Document, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	catalogers := opts.Catalogers
+	if catalogers == nil {
+		catalogers = DefaultCatalogers()
+	}
+
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening image: %w", err)
+	}
+	var src blobSource = &tarSource{path: imagePath}
+	if info.IsDir() {
+		src = &dirSource{dir: imagePath}
+	}
+
+	img, err := readImage(src, opts.Platform)
+	if err != nil {
+		return nil, fmt.Errorf("reading image: %w", err)
+	}
+
+	files := map[string]*imageFile{}
+	metadata := memFS{}
+	for i, layer := range img.Layers {
+		if err := readLayer(src, layer, i, files, metadata); err != nil {
+			return nil, fmt.Errorf("reading layer %s: %w", layer, err)
+		}
+	}
+
+	doc := &sbom.Document{
+		Metadata: &sbom.Metadata{
+			Name:    imageName(img, opts),
+			Version: "1",
+			Date:    timestamppb.Now(),
+			Tools:   []*sbom.Tool{{Name: "protobom"}},
+			Authors: []*sbom.Person{},
+		},
+		RootElements: []string{},
+		Nodes:        []*sbom.Node{},
+		Edges:        []*sbom.Edge{},
+	}
+
+	imageNode := imageNode(img, opts)
+	doc.Nodes = append(doc.Nodes, imageNode)
+	doc.RootElements = append(doc.RootElements, imageNode.Id)
+
+	paths := []string{}
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	packages := catalog.NewDocument()
+	for _, c := range catalogers {
+		found, err := c.Catalog(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("running %s cataloger: %w", c.Name(), err)
+		}
+		catalog.Merge(packages, pruneMissingFiles(found, files))
+	}
+
+	// File nodes are created from the image, with the hashes of the files
+	// as found in the last layer that wrote them.
+	for _, p := range paths {
+		n := catalog.FileNode(p)
+		for algo, value := range files[p].hashes {
+			n.Hashes[algo] = value
+		}
+		doc.Nodes = append(doc.Nodes, n)
+	}
+	catalog.Merge(doc, packages)
+
+	owned := map[string]struct{}{}
+	for _, e := range packages.Edges {
+		if e.Type != sbom.Edge_contains {
+			continue
+		}
+		for _, id := range e.To {
+			owned[id] = struct{}{}
+		}
+	}
+
+	contents := catalog.TopLevelPackages(packages)
+	for _, p := range paths {
+		if id := catalog.FileNodeID(p); !isOwned(owned, id) {
+			contents = append(contents, id)
+		}
+	}
+	if len(contents) > 0 {
+		doc.Edges = append([]*sbom.Edge{{
+			Type: sbom.Edge_contains,
+			From: imageNode.Id,
+			To:   contents,
+		}}, doc.Edges...)
+	}
+	return doc, nil
+}
+
+func isOwned(owned map[string]struct{}, id string) bool {
+	_, ok := owned[id]
+	return ok
+}
+
+// pruneMissingFiles removes from a cataloger result the files listed in the
+// package databases that are not present in the image.
+func pruneMissingFiles(doc *sbom.Document, files map[string]*imageFile) *sbom.Document {
+	present := map[string]struct{}{}
+	for p := range files {
+		present[catalog.FileNodeID(p)] = struct{}{}
+	}
+
+	removed := map[string]struct{}{}
+	nodes := []*sbom.Node{}
+	for _, n := range doc.Nodes {
+		if n.Type == sbom.Node_FILE {
+			if _, ok := present[n.Id]; !ok {
+				removed[n.Id] = struct{}{}
+				continue
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	doc.Nodes = nodes
+
+	edges := []*sbom.Edge{}
+	for _, e := range doc.Edges {
+		to := []string{}
+		for _, id := range e.To {
+			if _, ok := removed[id]; !ok {
+				to = append(to, id)
+			}
+		}
+		if len(to) == 0 {
+			continue
+		}
+		e.To = to
+		edges = append(edges, e)
+	}
+	doc.Edges = edges
+	return doc
+}
+
+// readLayer applies a layer to the files of the image, hashing the regular
+// files and processing the whiteouts that delete files of lower layers.
+func readLayer(src blobSource, name string, layer int, files map[string]*imageFile, metadata memFS) error {
+	tr, closer, err := openLayer(src, name)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading layer tarball: %w", err)
+		}
+
+		p := cleanPath(hdr.Name)
+		dir, base := path.Split(p)
+		switch {
+		case base == ".wh..wh..opq":
+			// Opaque whiteouts hide the contents of the directory in lower layers
+			deleteTree(files, metadata, strings.TrimSuffix(dir, "/"), layer, false)
+			continue
+		case strings.HasPrefix(base, ".wh."):
+			deleteTree(files, metadata, path.Join(dir, strings.TrimPrefix(base, ".wh.")), layer, true)
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			f, data, err := readFile(tr, p, hdr.Size)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", p, err)
+			}
+			f.layer = layer
+			files[p] = f
+			if data != nil {
+				metadata[p] = data
+			} else {
+				delete(metadata, p)
+			}
+		case tar.TypeLink:
+			target := cleanPath(hdr.Linkname)
+			if f, ok := files[target]; ok {
+				files[p] = &imageFile{hashes: f.hashes, layer: layer}
+				if data, ok := metadata[target]; ok {
+					metadata[p] = data
+				}
+			}
+		default:
+			// Directories, symlinks and devices are not recorded as files.
+			// A file replaced by one of them is removed.
+			delete(files, p)
+			delete(metadata, p)
+		}
+	}
+}
+
+// readFile hashes a file from a layer, keeping its contents if it is one
+// of the files the catalogers need.
+func readFile(r io.Reader, p string, size int64) (*imageFile, []byte, error) {
+	h1 := sha1.New()
+	h256 := sha256.New()
+	w := io.MultiWriter(h1, h256)
+
+	var data []byte
+	if isMetadata(p) && size <= maxMetadataSize {
+		buf, err := io.ReadAll(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = buf
+		if _, err := w.Write(buf); err != nil {
+			return nil, nil, err
+		}
+	} else if _, err := io.Copy(w, r); err != nil {
+		return nil, nil, err
+	}
+
+	return &imageFile{hashes: map[string]string{
+		"SHA1":   hex.EncodeToString(h1.Sum(nil)),
+		"SHA256": hex.EncodeToString(h256.Sum(nil)),
+	}}, data, nil
+}
+
+func isMetadata(p string) bool {
+	for _, prefix := range metadataPaths {
+		if p == prefix || (strings.HasSuffix(prefix, "/") && strings.HasPrefix(p, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteTree removes a path and everything under it written by layers
+// lower than the current one. When self is false, only the children of the
+// path are removed.
+func deleteTree(files map[string]*imageFile, metadata memFS, p string, layer int, self bool) {
+	prefix := p + "/"
+	if p == "" || p == "." {
+		prefix = ""
+	}
+	for name, f := range files {
+		if f.layer >= layer {
+			continue
+		}
+		if (self && name == p) || strings.HasPrefix(name, prefix) {
+			delete(files, name)
+			delete(metadata, name)
+		}
+	}
+}
+
+// imageReference returns the reference of the image, the name in the
+// options or the one recorded in the layout
+func imageReference(img *image, opts *Options) string {
+	if opts.Name != "" {
+		return opts.Name
+	}
+	return img.Reference
+}
+
+// splitReference splits an image reference in its repository and tag,
+// dropping the digest. "registry.example.com/app:1.0@sha256:..." returns
+// "registry.example.com/app" and "1.0".
+func splitReference(ref string) (repository, tag string) {
+	repository, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(repository, ":"); i != -1 && i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return strings.ToLower(repository), tag
+}
+
+// imageName returns the name of the image used in its package URL, the
+// last element of the repository.
+func imageName(img *image, opts *Options) string {
+	name, _ := splitReference(imageReference(img, opts))
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		return "image"
+	}
+	return name
+}
+
+// imageNode returns the root node describing the image
+func imageNode(img *image, opts *Options) *sbom.Node {
+	algo, digest, _ := strings.Cut(img.Digest, ":")
+	n := &sbom.Node{
+		Id:                 catalog.PackageNodeID(algo, digest),
+		Type:               sbom.Node_PACKAGE,
+		Name:               imageName(img, opts),
+		Version:            img.Digest,
+		PrimaryPurpose:     "CONTAINER",
+		Description:        "container image",
+		Licenses:           []string{},
+		Hashes:             map[string]string{strings.ToUpper(algo): digest},
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+
+	// The oci package URL type defines the arch, repository_url and tag
+	// qualifiers. The repository is only known when the reference has a
+	// path, a bare name doesn't say which registry it comes from.
+	qualifiers := map[string]string{}
+	if img.Config.Architecture != "" {
+		qualifiers["arch"] = img.Config.Architecture
+	}
+	repository, tag := splitReference(imageReference(img, opts))
+	if strings.Contains(repository, "/") {
+		qualifiers["repository_url"] = repository
+	}
+	if tag != "" {
+		qualifiers["tag"] = tag
+	}
+
+	n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+		Type:  "purl",
+		Value: purl.New("oci", "", n.Name, img.Digest, qualifiers).String(),
+	})
+	return n
+}
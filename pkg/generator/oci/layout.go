@@ -0,0 +1,337 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	mediaTypeOCIIndex    = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerList  = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// annotationRefName is the annotation with the image reference in the
+	// index of an OCI layout
+	annotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// descriptor points to a blob in an OCI layout
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *platform         `json:"platform,omitempty"`
+}
+
+type platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// String returns the platform as os/arch[/variant]
+func (p *platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+type index struct {
+	MediaType string       `json:"mediaType"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Config    descriptor   `json:"config"`
+	Layers    []descriptor `json:"layers"`
+}
+
+// dockerManifest is an entry in the manifest.json of docker save tarballs
+type dockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+type imageConfig struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// image is the data of an image read from an OCI layout or docker tarball
+type image struct {
+	// Digest of the manifest, or of the config for docker tarballs which
+	// don't include the manifest.
+	Digest    string
+	MediaType string
+	Reference string
+	Config    imageConfig
+	Layers    []string
+}
+
+// blobSource opens the files of an image layout
+type blobSource interface {
+	Open(name string) (io.ReadCloser, error)
+}
+
+// dirSource reads an image layout from a directory
+type dirSource struct {
+	dir string
+}
+
+func (ds *dirSource) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(ds.dir, filepath.FromSlash(name)))
+}
+
+// tarSource reads an image layout from a tarball. Tarballs can't be read
+// randomly, the archive is scanned each time a file is opened.
+type tarSource struct {
+	path string
+}
+
+type tarEntry struct {
+	io.Reader
+	f *os.File
+}
+
+func (te *tarEntry) Close() error {
+	return te.f.Close()
+}
+
+func (ts *tarSource) Open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(ts.path)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("reading tarball: %w", err)
+		}
+		if cleanPath(hdr.Name) == cleanPath(name) {
+			return &tarEntry{Reader: tr, f: f}, nil
+		}
+	}
+}
+
+// cleanPath normalizes a path in an archive, removing the leading slash
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// blobPath returns the path of a blob in an OCI layout
+func blobPath(digest string) (string, error) {
+	algo, hexDigest, ok := strings.Cut(digest, ":")
+	if !ok || algo == "" || hexDigest == "" || strings.ContainsAny(hexDigest, "/.") {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return path.Join("blobs", algo, hexDigest), nil
+}
+
+// readJSON decodes a JSON file from the source
+func readJSON(src blobSource, name string, v interface{}) error {
+	f, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return nil
+}
+
+// readImage reads the image description from an OCI layout or, if there is
+// no index.json, from the manifest.json written by docker save.
+func readImage(src blobSource, platformName string) (*image, error) {
+	idx := &index{}
+	err := readJSON(src, "index.json", idx)
+	switch {
+	case err == nil:
+		return readOCIImage(src, idx, platformName)
+	case errors.Is(err, os.ErrNotExist):
+		return readDockerImage(src)
+	default:
+		return nil, fmt.Errorf("reading image index: %w", err)
+	}
+}
+
+// readOCIImage reads the image selected from an OCI index. Nested indexes
+// (multi architecture images) are resolved using the platform.
+func readOCIImage(src blobSource, idx *index, platformName string) (*image, error) {
+	reference := ""
+	for {
+		desc, err := selectManifest(idx, platformName)
+		if err != nil {
+			return nil, err
+		}
+		if ref := desc.Annotations[annotationRefName]; ref != "" && reference == "" {
+			reference = ref
+		}
+		name, err := blobPath(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		if desc.MediaType == mediaTypeOCIIndex || desc.MediaType == mediaTypeDockerList {
+			idx = &index{}
+			if err := readJSON(src, name, idx); err != nil {
+				return nil, fmt.Errorf("reading image index: %w", err)
+			}
+			continue
+		}
+
+		m := &manifest{}
+		if err := readJSON(src, name, m); err != nil {
+			return nil, fmt.Errorf("reading image manifest: %w", err)
+		}
+
+		img := &image{
+			Digest:    desc.Digest,
+			MediaType: desc.MediaType,
+			Reference: reference,
+			Layers:    []string{},
+		}
+		if img.MediaType == "" {
+			img.MediaType = mediaTypeOCIManifest
+		}
+
+		configPath, err := blobPath(m.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if err := readJSON(src, configPath, &img.Config); err != nil {
+			return nil, fmt.Errorf("reading image config: %w", err)
+		}
+
+		for _, l := range m.Layers {
+			p, err := blobPath(l.Digest)
+			if err != nil {
+				return nil, err
+			}
+			img.Layers = append(img.Layers, p)
+		}
+		return img, nil
+	}
+}
+
+// selectManifest returns the descriptor matching the platform or the first
+// one if no platform is specified.
+func selectManifest(idx *index, platformName string) (*descriptor, error) {
+	if len(idx.Manifests) == 0 {
+		return nil, errors.New("image index has no manifests")
+	}
+	if platformName == "" {
+		return &idx.Manifests[0], nil
+	}
+	for i := range idx.Manifests {
+		d := &idx.Manifests[i]
+		if d.Platform == nil {
+			continue
+		}
+		if d.Platform.String() == platformName || d.Platform.OS+"/"+d.Platform.Architecture == platformName {
+			return d, nil
+		}
+	}
+	// Single image layouts don't record the platform in the index
+	if len(idx.Manifests) == 1 && idx.Manifests[0].Platform == nil {
+		return &idx.Manifests[0], nil
+	}
+	return nil, fmt.Errorf("image has no manifest for platform %s", platformName)
+}
+
+// readDockerImage reads the first image in a docker save tarball. The
+// tarball does not include the manifest, the image is identified by the
+// digest of its configuration as docker does.
+func readDockerImage(src blobSource) (*image, error) {
+	manifests := []dockerManifest{}
+	if err := readJSON(src, "manifest.json", &manifests); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("not an OCI layout or docker image tarball")
+		}
+		return nil, fmt.Errorf("reading docker manifest: %w", err)
+	}
+	if len(manifests) == 0 {
+		return nil, errors.New("docker manifest lists no images")
+	}
+	m := manifests[0]
+
+	f, err := src.Open(m.Config)
+	if err != nil {
+		return nil, fmt.Errorf("opening image config: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading image config: %w", err)
+	}
+
+	img := &image{
+		MediaType: "application/vnd.docker.distribution.manifest.v2+json",
+		Layers:    m.Layers,
+	}
+	if err := json.Unmarshal(data, &img.Config); err != nil {
+		return nil, fmt.Errorf("decoding image config: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	img.Digest = "sha256:" + hex.EncodeToString(sum[:])
+	if len(m.RepoTags) > 0 {
+		img.Reference = m.RepoTags[0]
+	}
+	return img, nil
+}
+
+// openLayer returns a tar reader of a layer, decompressing it if needed
+func openLayer(src blobSource, name string) (*tar.Reader, io.Closer, error) {
+	f, err := src.Open(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening layer: %w", err)
+	}
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		f.Close()
+		return nil, nil, fmt.Errorf("reading layer: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("decompressing layer: %w", err)
+		}
+		return tar.NewReader(gz), f, nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		f.Close()
+		return nil, nil, errors.New("zstd compressed layers are not supported")
+	default:
+		return tar.NewReader(br), f, nil
+	}
+}
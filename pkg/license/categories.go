@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package license
+
+import "strings"
+
+// License categories. A license can belong to more than one category, for
+// example AGPL-3.0 is copyleft, strong-copyleft and network-copyleft.
+const (
+	CategoryPermissive      = "permissive"
+	CategoryPublicDomain    = "public-domain"
+	CategoryCopyleft        = "copyleft"
+	CategoryWeakCopyleft    = "weak-copyleft"
+	CategoryStrongCopyleft  = "strong-copyleft"
+	CategoryNetworkCopyleft = "network-copyleft"
+	CategoryNonCommercial   = "non-commercial"
+	CategoryProprietary     = "proprietary"
+	CategoryUnknown         = "unknown"
+)
+
+var (
+	permissive      = []string{CategoryPermissive}
+	publicDomain    = []string{CategoryPublicDomain, CategoryPermissive}
+	weakCopyleft    = []string{CategoryCopyleft, CategoryWeakCopyleft}
+	strongCopyleft  = []string{CategoryCopyleft, CategoryStrongCopyleft}
+	networkCopyleft = []string{CategoryCopyleft, CategoryStrongCopyleft, CategoryNetworkCopyleft}
+	nonCommercial   = []string{CategoryNonCommercial}
+)
+
+// categories maps the SPDX license IDs, without their -only and -or-later
+// suffixes, to the categories they belong to.
+var categories = map[string][]string{
+	"0BSD":            permissive,
+	"AFL-3.0":         permissive,
+	"Apache-1.1":      permissive,
+	"Apache-2.0":      permissive,
+	"Artistic-2.0":    permissive,
+	"BSD-1-Clause":    permissive,
+	"BSD-2-Clause":    permissive,
+	"BSD-3-Clause":    permissive,
+	"BSD-4-Clause":    permissive,
+	"BSL-1.0":         permissive,
+	"bzip2-1.0.6":     permissive,
+	"curl":            permissive,
+	"FTL":             permissive,
+	"ISC":             permissive,
+	"Libpng":          permissive,
+	"libpng-2.0":      permissive,
+	"MIT":             permissive,
+	"MIT-0":           permissive,
+	"NCSA":            permissive,
+	"OpenSSL":         permissive,
+	"PHP-3.01":        permissive,
+	"PostgreSQL":      permissive,
+	"PSF-2.0":         permissive,
+	"Python-2.0":      permissive,
+	"Ruby":            permissive,
+	"TCL":             permissive,
+	"X11":             permissive,
+	"Zlib":            permissive,
+	"ZPL-2.1":         permissive,
+	"CC-BY-4.0":       permissive,
+	"CC0-1.0":         publicDomain,
+	"Unlicense":       publicDomain,
+	"WTFPL":           publicDomain,
+	"CDDL-1.0":        weakCopyleft,
+	"CDDL-1.1":        weakCopyleft,
+	"EPL-1.0":         weakCopyleft,
+	"EPL-2.0":         weakCopyleft,
+	"LGPL-2.0":        weakCopyleft,
+	"LGPL-2.1":        weakCopyleft,
+	"LGPL-3.0":        weakCopyleft,
+	"MPL-1.1":         weakCopyleft,
+	"MPL-2.0":         weakCopyleft,
+	"CC-BY-SA-4.0":    weakCopyleft,
+	"GPL-1.0":         strongCopyleft,
+	"GPL-2.0":         strongCopyleft,
+	"GPL-3.0":         strongCopyleft,
+	"EUPL-1.1":        strongCopyleft,
+	"EUPL-1.2":        strongCopyleft,
+	"OSL-3.0":         strongCopyleft,
+	"Sleepycat":       strongCopyleft,
+	"AGPL-1.0":        networkCopyleft,
+	"AGPL-3.0":        networkCopyleft,
+	"SSPL-1.0":        networkCopyleft,
+	"CC-BY-NC-4.0":    nonCommercial,
+	"CC-BY-NC-SA-4.0": nonCommercial,
+	"CC-BY-NC-ND-4.0": nonCommercial,
+}
+
+// BaseID returns the license ID without the -only and -or-later suffixes
+// used by the SPDX license list to mark the GNU license versions.
+func BaseID(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	id = strings.TrimSuffix(id, "-only")
+	id = strings.TrimSuffix(id, "-or-later")
+	return id
+}
+
+// Categories returns the categories of a license. Licenses not known are
+// returned in the unknown category, and LicenseRefs are proprietary.
+func Categories(id string) []string {
+	if strings.HasPrefix(id, "LicenseRef-") || strings.Contains(id, ":LicenseRef-") {
+		return []string{CategoryProprietary}
+	}
+	base := BaseID(id)
+	if c, ok := categories[base]; ok {
+		return c
+	}
+	for k, v := range categories {
+		if strings.EqualFold(k, base) {
+			return v
+		}
+	}
+	return []string{CategoryUnknown}
+}
+
+// InCategory returns true if a license belongs to a category
+func InCategory(id, category string) bool {
+	for _, c := range Categories(id) {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package license
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed SPDX license expression
+type Expression interface {
+	// String returns the expression in its normalized SPDX form
+	String() string
+
+	// Licenses returns all the licenses referenced in the expression
+	Licenses() []*License
+}
+
+// License is a single license in an expression, optionally with an
+// exception attached using the WITH operator.
+type License struct {
+	ID        string
+	Plus      bool
+	Exception string
+}
+
+// And is a conjunction, both sides of the expression apply
+type And struct {
+	Left, Right Expression
+}
+
+// Or is a disjunction, the licensee can choose either side
+type Or struct {
+	Left, Right Expression
+}
+
+func (l *License) String() string {
+	s := l.ID
+	if l.Plus {
+		s += "+"
+	}
+	if l.Exception != "" {
+		s += " WITH " + l.Exception
+	}
+	return s
+}
+
+func (l *License) Licenses() []*License {
+	return []*License{l}
+}
+
+func (a *And) String() string {
+	return fmt.Sprintf("%s AND %s", wrapExpression(a.Left, a), wrapExpression(a.Right, a))
+}
+
+func (a *And) Licenses() []*License {
+	return append(a.Left.Licenses(), a.Right.Licenses()...)
+}
+
+func (o *Or) String() string {
+	return fmt.Sprintf("%s OR %s", wrapExpression(o.Left, o), wrapExpression(o.Right, o))
+}
+
+func (o *Or) Licenses() []*License {
+	return append(o.Left.Licenses(), o.Right.Licenses()...)
+}
+
+// wrapExpression adds parenthesis to a subexpression when its operator binds
+// looser than its parent's.
+func wrapExpression(e, parent Expression) string {
+	if _, ok := parent.(*And); ok {
+		if _, ok := e.(*Or); ok {
+			return "(" + e.String() + ")"
+		}
+	}
+	return e.String()
+}
+
+// ParseExpression parses an SPDX license expression. Operators are accepted
+// in any case. AND binds tighter than OR, and WITH tighter than both.
+func ParseExpression(s string) (Expression, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("license expression is empty")
+	}
+
+	p := &expressionParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", s, err)
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("parsing %q: unexpected token %q", s, p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+// tokenize splits a license expression into identifiers, operators and
+// parenthesis.
+func tokenize(s string) ([]string, error) {
+	tokens := []string{}
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-.+:_", r):
+			current.WriteRune(r)
+		default:
+			return nil, fmt.Errorf("invalid character %q in license expression", r)
+		}
+	}
+	flush()
+	return tokens, nil
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) isOperator(op string) bool {
+	return strings.EqualFold(p.peek(), op)
+}
+
+func (p *expressionParser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("AND") {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseTerm() (Expression, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return e, nil
+	case tok == ")" || isOperatorToken(tok):
+		return nil, fmt.Errorf("unexpected token %q", tok)
+	}
+
+	p.pos++
+	l := &License{ID: tok}
+	if strings.HasSuffix(l.ID, "+") {
+		l.ID = strings.TrimSuffix(l.ID, "+")
+		l.Plus = true
+	}
+
+	if p.isOperator("WITH") {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" || isOperatorToken(exception) {
+			return nil, fmt.Errorf("WITH operator must be followed by an exception")
+		}
+		p.pos++
+		l.Exception = exception
+	}
+	return l, nil
+}
+
+func isOperatorToken(tok string) bool {
+	switch strings.ToUpper(tok) {
+	case "AND", "OR", "WITH":
+		return true
+	}
+	return false
+}
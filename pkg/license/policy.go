@@ -0,0 +1,299 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+const (
+	fieldLicenses         = "licenses"
+	fieldLicenseConcluded = "licenseConcluded"
+	noAssertion           = "NOASSERTION"
+	none                  = "NONE"
+)
+
+// Policy defines which licenses are acceptable in the nodes of a document.
+// Denials take precedence over the allow lists. When both allow lists are
+// empty, any license not explicitly denied is accepted.
+type Policy struct {
+	// Allowed lists the SPDX IDs of the accepted licenses
+	Allowed []string `json:"allowed,omitempty"`
+
+	// Denied lists the SPDX IDs of licenses that must not be used
+	Denied []string `json:"denied,omitempty"`
+
+	// AllowedCategories accepts all licenses in the listed categories
+	AllowedCategories []string `json:"allowedCategories,omitempty"`
+
+	// DeniedCategories rejects all licenses in the listed categories
+	DeniedCategories []string `json:"deniedCategories,omitempty"`
+
+	// Exceptions accept licenses for the components of specific suppliers
+	Exceptions []*Exception `json:"exceptions,omitempty"`
+
+	// RequireLicense flags nodes without any license information
+	RequireLicense bool `json:"requireLicense,omitempty"`
+
+	// NodeTypes limits the evaluation to nodes of the listed types (PACKAGE,
+	// FILE). When empty, all nodes are evaluated.
+	NodeTypes []string `json:"nodeTypes,omitempty"`
+}
+
+// Exception allows licenses otherwise rejected by the policy in the nodes
+// supplied by a specific person or organization.
+type Exception struct {
+	Supplier string   `json:"supplier"`
+	Licenses []string `json:"licenses"`
+}
+
+// Violation is a node license that does not comply with the policy
+type Violation struct {
+	NodeID     string   `json:"nodeId"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version,omitempty"`
+	Field      string   `json:"field"`
+	Expression string   `json:"expression"`
+	Licenses   []string `json:"licenses"`
+	Reason     string   `json:"reason"`
+
+	// Path lists the node IDs from a root element of the document to the
+	// node through contains and dependsOn edges.
+	Path []string `json:"path"`
+}
+
+// String returns a human readable representation of the violation
+func (v *Violation) String() string {
+	return fmt.Sprintf(
+		"%s %s (%s %q): %s [%s]", v.Name, v.Version, v.Field, v.Expression,
+		v.Reason, strings.Join(v.Path, " -> "),
+	)
+}
+
+// LoadPolicy reads a policy in JSON
+func LoadPolicy(r io.Reader) (*Policy, error) {
+	p := &Policy{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, fmt.Errorf("decoding policy: %w", err)
+	}
+	return p, nil
+}
+
+// Evaluate checks the licenses of all nodes in the document against the
+// policy and returns the violations found. Both the declared licenses and
+// the concluded license are evaluated as license expressions.
+func (p *Policy) Evaluate(doc *sbom.Document) ([]*Violation, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("unable to evaluate policy on nil document")
+	}
+
+	paths := doc.RootPaths()
+	violations := []*Violation{}
+	for _, n := range doc.Nodes {
+		if !p.appliesTo(n) {
+			continue
+		}
+
+		nodeViolations := []*Violation{}
+		declared := []string{}
+		for _, l := range n.Licenses {
+			if l = strings.TrimSpace(l); l != "" && l != noAssertion {
+				declared = append(declared, l)
+			}
+		}
+
+		// Multiple declared licenses all apply to the node
+		if len(declared) > 0 {
+			expr := strings.Join(wrapAll(declared), " AND ")
+			if v := p.evaluateExpression(n, fieldLicenses, expr); v != nil {
+				nodeViolations = append(nodeViolations, v)
+			}
+		}
+
+		concluded := strings.TrimSpace(n.LicenseConcluded)
+		if concluded != "" && concluded != noAssertion {
+			if v := p.evaluateExpression(n, fieldLicenseConcluded, concluded); v != nil {
+				nodeViolations = append(nodeViolations, v)
+			}
+		}
+
+		if p.RequireLicense && len(declared) == 0 && (concluded == "" || concluded == noAssertion) {
+			nodeViolations = append(nodeViolations, &Violation{
+				NodeID:   n.Id,
+				Name:     n.Name,
+				Version:  n.Version,
+				Field:    fieldLicenses,
+				Licenses: []string{},
+				Reason:   "node has no license information",
+			})
+		}
+
+		for _, v := range nodeViolations {
+			v.Path = paths.PathTo(n.Id)
+		}
+		violations = append(violations, nodeViolations...)
+	}
+	return violations, nil
+}
+
+// appliesTo returns true if the policy should be evaluated on the node
+func (p *Policy) appliesTo(n *sbom.Node) bool {
+	if len(p.NodeTypes) == 0 {
+		return true
+	}
+	for _, t := range p.NodeTypes {
+		if strings.EqualFold(t, n.Type.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateExpression parses and evaluates a license expression found in a
+// node field. It returns nil if the expression complies with the policy.
+func (p *Policy) evaluateExpression(n *sbom.Node, field, expression string) *Violation {
+	v := &Violation{
+		NodeID:     n.Id,
+		Name:       n.Name,
+		Version:    n.Version,
+		Field:      field,
+		Expression: expression,
+		Licenses:   []string{},
+	}
+
+	if strings.EqualFold(expression, none) {
+		return nil
+	}
+
+	expr, err := ParseExpression(expression)
+	if err != nil {
+		v.Reason = fmt.Sprintf("invalid license expression: %v", err)
+		return v
+	}
+
+	ok, failing := p.evaluate(n, expr)
+	if ok {
+		return nil
+	}
+
+	reasons := []string{}
+	for _, f := range failing {
+		v.Licenses = append(v.Licenses, f.license.String())
+		reasons = append(reasons, fmt.Sprintf("%s %s", f.license.ID, f.reason))
+	}
+	v.Reason = strings.Join(reasons, ", ")
+	return v
+}
+
+// licenseFailure records why a license in an expression was rejected
+type licenseFailure struct {
+	license *License
+	reason  string
+}
+
+// evaluate walks the expression tree. An OR expression complies when any of
+// its branches complies, an AND expression requires both branches to comply.
+func (p *Policy) evaluate(n *sbom.Node, expr Expression) (bool, []licenseFailure) {
+	switch e := expr.(type) {
+	case *Or:
+		okLeft, failLeft := p.evaluate(n, e.Left)
+		if okLeft {
+			return true, nil
+		}
+		okRight, failRight := p.evaluate(n, e.Right)
+		if okRight {
+			return true, nil
+		}
+		return false, append(failLeft, failRight...)
+	case *And:
+		okLeft, failLeft := p.evaluate(n, e.Left)
+		okRight, failRight := p.evaluate(n, e.Right)
+		return okLeft && okRight, append(failLeft, failRight...)
+	case *License:
+		if reason := p.checkLicense(n, e); reason != "" {
+			return false, []licenseFailure{{license: e, reason: reason}}
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// checkLicense returns the reason a single license is rejected by the
+// policy, or an empty string if the license is accepted.
+func (p *Policy) checkLicense(n *sbom.Node, l *License) string {
+	if p.isException(n, l.ID) {
+		return ""
+	}
+
+	if matchesID(p.Denied, l.ID) {
+		return "is denied"
+	}
+
+	for _, c := range p.DeniedCategories {
+		if InCategory(l.ID, c) {
+			return fmt.Sprintf("is in denied category %s", c)
+		}
+	}
+
+	if len(p.Allowed) == 0 && len(p.AllowedCategories) == 0 {
+		return ""
+	}
+
+	if matchesID(p.Allowed, l.ID) {
+		return ""
+	}
+
+	for _, c := range p.AllowedCategories {
+		if InCategory(l.ID, c) {
+			return ""
+		}
+	}
+
+	return "is not allowed"
+}
+
+// isException returns true if the license is accepted for one of the
+// node suppliers.
+func (p *Policy) isException(n *sbom.Node, id string) bool {
+	for _, e := range p.Exceptions {
+		for _, s := range n.Suppliers {
+			if !strings.EqualFold(strings.TrimSpace(s.Name), strings.TrimSpace(e.Supplier)) {
+				continue
+			}
+			if matchesID(e.Licenses, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesID checks if a license ID is in a list. IDs in the list without
+// the GNU -only or -or-later suffixes match all the variants.
+func matchesID(list []string, id string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, id) || strings.EqualFold(item, BaseID(id)) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapAll puts each expression in parenthesis so they can be joined
+func wrapAll(expressions []string) []string {
+	if len(expressions) == 1 {
+		return expressions
+	}
+	ret := []string{}
+	for _, e := range expressions {
+		ret = append(ret, "("+e+")")
+	}
+	return ret
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package osv
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/purl"
+)
+
+// purlEcosystems maps package URL types to OSV ecosystems
+var purlEcosystems = map[string]string{
+	"apk":      "Alpine",
+	"cargo":    "crates.io",
+	"composer": "Packagist",
+	"cran":     "CRAN",
+	"deb":      "Debian",
+	"gem":      "RubyGems",
+	"golang":   "Go",
+	"hackage":  "Hackage",
+	"hex":      "Hex",
+	"maven":    "Maven",
+	"npm":      "npm",
+	"nuget":    "NuGet",
+	"pub":      "Pub",
+	"pypi":     "PyPI",
+}
+
+// distroEcosystems maps the namespaces of OS package URLs to the ecosystem
+// of the distribution.
+var distroEcosystems = map[string]string{
+	"alpine": "Alpine",
+	"debian": "Debian",
+	"ubuntu": "Ubuntu",
+	"wolfi":  "Wolfi",
+}
+
+// ecosystemComparers lists the version comparison of each ecosystem. Any
+// ecosystem not listed here uses compareGeneric.
+var ecosystemComparers = map[string]compareFunc{
+	"Alpine":    compareAPK,
+	"Wolfi":     compareAPK,
+	"Debian":    compareDebian,
+	"Ubuntu":    compareDebian,
+	"PyPI":      comparePEP440,
+	"Go":        compareSemver,
+	"npm":       compareSemver,
+	"crates.io": compareSemver,
+	"Hex":       compareSemver,
+	"Pub":       compareSemver,
+	"NuGet":     compareSemver,
+	"Packagist": compareSemver,
+}
+
+// baseEcosystem strips the release from an ecosystem name, for example
+// "Debian:11" becomes "Debian".
+func baseEcosystem(ecosystem string) string {
+	base, _, _ := strings.Cut(ecosystem, ":")
+	return base
+}
+
+// ecosystemRelease returns the release part of an ecosystem name
+func ecosystemRelease(ecosystem string) string {
+	_, release, _ := strings.Cut(ecosystem, ":")
+	release, _, _ = strings.Cut(release, ":")
+	return release
+}
+
+var pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// packageKey builds the database index key of a package
+func packageKey(ecosystem, name string) string {
+	if ecosystem == "PyPI" {
+		name = pypiNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return ecosystem + "/" + name
+}
+
+// purlPackage returns the OSV ecosystem and the package names to look up
+// for a package URL. OS packages are also looked up by their source package
+// as distributions publish their advisories by source.
+func purlPackage(p *purl.PackageURL) (ecosystem string, names []string) {
+	ecosystem, ok := purlEcosystems[p.Type]
+	if !ok {
+		return "", nil
+	}
+
+	switch p.Type {
+	case "apk", "deb":
+		if distro, ok := distroEcosystems[strings.ToLower(p.Namespace)]; ok {
+			ecosystem = distro
+		}
+		names = []string{p.Name}
+		if upstream, _, _ := strings.Cut(p.Qualifiers["upstream"], "@"); upstream != "" && upstream != p.Name {
+			names = append(names, upstream)
+		}
+		return ecosystem, names
+	case "maven":
+		if p.Namespace != "" {
+			return ecosystem, []string{p.Namespace + ":" + p.Name}
+		}
+	case "npm", "golang", "composer":
+		if p.Namespace != "" {
+			return ecosystem, []string{p.Namespace + "/" + p.Name}
+		}
+	}
+	return ecosystem, []string{p.Name}
+}
+
+// releaseMatches checks if the distribution release of an ecosystem (eg
+// "v3.18" in "Alpine:v3.18") is the release in the distro qualifier of a
+// package URL (eg "alpine-3.18.4"). The distro version is cut to as many
+// components as the release before comparing them. Missing data on either
+// side matches.
+func releaseMatches(ecosystem string, p *purl.PackageURL) bool {
+	release := strings.TrimPrefix(ecosystemRelease(ecosystem), "v")
+	version := distroVersion(p.Qualifiers["distro"])
+	if release == "" || version == "" {
+		return true
+	}
+	parts := strings.Split(version, ".")
+	if n := strings.Count(release, ".") + 1; len(parts) > n {
+		parts = parts[:n]
+	}
+	return strings.Join(parts, ".") == release
+}
+
+// distroVersion strips the distribution ID from a distro qualifier,
+// "alpine-3.18.4" returns "3.18.4". Qualifiers with only the ID return an
+// empty string.
+func distroVersion(distro string) string {
+	if i := strings.LastIndex(distro, "-"); i >= 0 {
+		distro = distro[i+1:]
+	}
+	if distro == "" || distro[0] < '0' || distro[0] > '9' {
+		return ""
+	}
+	return distro
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package osv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Finding records a vulnerability affecting a node of an SBOM
+type Finding struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Summary   string   `json:"summary,omitempty"`
+	NodeID    string   `json:"node_id"`
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Purl      string   `json:"purl"`
+	Ecosystem string   `json:"ecosystem"`
+	Fixed     []string `json:"fixed,omitempty"`
+
+	// Path lists the node IDs from a root element to the affected node
+	Path []string `json:"path"`
+
+	// Entry is the OSV record of the vulnerability
+	Entry *Entry `json:"-"`
+}
+
+// String returns a one line description of the finding
+func (f *Finding) String() string {
+	s := fmt.Sprintf("%s: %s %s", f.ID, f.Name, f.Version)
+	if len(f.Fixed) > 0 {
+		s += fmt.Sprintf(" (fixed in %s)", strings.Join(f.Fixed, ", "))
+	}
+	return s + " [" + strings.Join(f.Path, " -> ") + "]"
+}
+
+// Match looks up the nodes of an SBOM in the database by their package URL
+// and returns the vulnerabilities affecting their versions.
+func (db *Database) Match(doc *sbom.Document) []*Finding {
+	findings := []*Finding{}
+	paths := doc.RootPaths()
+	for _, n := range doc.Nodes {
+		p, err := purl.Parse(n.Purl())
+		if err != nil {
+			continue
+		}
+		version := p.Version
+		if version == "" {
+			version = n.Version
+		}
+		if version == "" {
+			continue
+		}
+
+		ecosystem, names := purlPackage(p)
+		seen := map[string]struct{}{}
+		for _, name := range names {
+			for _, entry := range db.lookup(ecosystem, name) {
+				if _, ok := seen[entry.ID]; ok {
+					continue
+				}
+				affected, fixed := entry.affects(ecosystem, name, p, version)
+				if !affected {
+					continue
+				}
+				seen[entry.ID] = struct{}{}
+				findings = append(findings, &Finding{
+					ID:        entry.ID,
+					Aliases:   entry.Aliases,
+					Summary:   entry.Summary,
+					NodeID:    n.Id,
+					Name:      n.Name,
+					Version:   version,
+					Purl:      p.String(),
+					Ecosystem: ecosystem,
+					Fixed:     fixed,
+					Path:      paths.PathTo(n.Id),
+					Entry:     entry,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].NodeID != findings[j].NodeID {
+			return findings[i].NodeID < findings[j].NodeID
+		}
+		return findings[i].ID < findings[j].ID
+	})
+	return findings
+}
+
+// affects checks if a package version is affected by the entry. It returns
+// the versions where the vulnerability is fixed.
+func (e *Entry) affects(ecosystem, name string, p *purl.PackageURL, version string) (bool, []string) {
+	fixed := []string{}
+	isAffected := false
+	for i := range e.Affected {
+		a := &e.Affected[i]
+		if baseEcosystem(a.Package.Ecosystem) != ecosystem ||
+			packageKey(ecosystem, a.Package.Name) != packageKey(ecosystem, name) ||
+			!releaseMatches(a.Package.Ecosystem, p) {
+			continue
+		}
+
+		compare, ok := ecosystemComparers[ecosystem]
+		if !ok {
+			compare = compareGeneric
+		}
+
+		for _, v := range a.Versions {
+			if compare(v, version) == 0 {
+				isAffected = true
+			}
+		}
+
+		for _, r := range a.Ranges {
+			rangeCompare := compare
+			switch r.Type {
+			case RangeSemVer:
+				rangeCompare = compareSemver
+			case RangeGit:
+				// Git ranges are commit hashes, they can't be compared
+				continue
+			}
+			if inRange(r.Events, version, rangeCompare) {
+				isAffected = true
+			}
+			for _, ev := range r.Events {
+				if ev.Fixed != "" {
+					fixed = append(fixed, ev.Fixed)
+				}
+			}
+		}
+	}
+	return isAffected, fixed
+}
+
+// inRange evaluates the events of a range as described in the OSV schema:
+// events are sorted by version and each one toggles the affected status.
+func inRange(events []Event, version string, compare compareFunc) bool {
+	eventVersion := func(ev *Event) string {
+		switch {
+		case ev.Introduced != "":
+			return ev.Introduced
+		case ev.Fixed != "":
+			return ev.Fixed
+		case ev.LastAffected != "":
+			return ev.LastAffected
+		default:
+			return ev.Limit
+		}
+	}
+
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		vi, vj := eventVersion(&sorted[i]), eventVersion(&sorted[j])
+		// Introduced "0" sorts before any version
+		if sorted[i].Introduced == "0" || sorted[j].Introduced == "0" {
+			return sorted[i].Introduced == "0" && sorted[j].Introduced != "0"
+		}
+		return compare(vi, vj) < 0
+	})
+
+	affected := false
+	for _, ev := range sorted {
+		switch {
+		case ev.Introduced != "":
+			if ev.Introduced == "0" || compare(version, ev.Introduced) >= 0 {
+				affected = true
+			}
+		case ev.Fixed != "":
+			if compare(version, ev.Fixed) >= 0 {
+				affected = false
+			}
+		case ev.LastAffected != "":
+			if compare(version, ev.LastAffected) > 0 {
+				affected = false
+			}
+		case ev.Limit != "":
+			if ev.Limit != "*" && compare(version, ev.Limit) >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// cvssMethods maps the OSV severity types to the rating methods in protobom
+var cvssMethods = map[string]string{
+	"CVSS_V2": "CVSSv2",
+	"CVSS_V3": "CVSSv3",
+	"CVSS_V4": "CVSSv4",
+}
+
+// Vulnerabilities converts a list of findings to protobom vulnerabilities,
+// grouping the nodes affected by the same entry.
+func Vulnerabilities(findings []*Finding) []*sbom.Vulnerability {
+	vulns := []*sbom.Vulnerability{}
+	index := map[string]*sbom.Vulnerability{}
+	for _, f := range findings {
+		v, ok := index[f.ID]
+		if !ok {
+			v = entryToVulnerability(f.Entry)
+			if v.Id == "" {
+				v.Id = f.ID
+			}
+			index[f.ID] = v
+			vulns = append(vulns, v)
+		}
+
+		a := &sbom.Affects{
+			NodeId: f.NodeID,
+			Versions: []*sbom.AffectedVersion{
+				{Version: f.Version, Status: sbom.AffectedVersion_affected},
+			},
+		}
+		for _, fixed := range f.Fixed {
+			a.Versions = append(a.Versions, &sbom.AffectedVersion{
+				Version: fixed, Status: sbom.AffectedVersion_unaffected,
+			})
+		}
+		v.Affects = append(v.Affects, a)
+	}
+	return vulns
+}
+
+// entryToVulnerability converts the data in an OSV entry to a protobom
+// vulnerability without any affected nodes.
+func entryToVulnerability(e *Entry) *sbom.Vulnerability {
+	v := &sbom.Vulnerability{
+		SourceName: "OSV",
+		Aliases:    []string{},
+		Ratings:    []*sbom.Rating{},
+		Advisories: []*sbom.ExternalReference{},
+		Affects:    []*sbom.Affects{},
+	}
+	if e == nil {
+		return v
+	}
+
+	v.Id = e.ID
+	v.SourceUrl = "https://osv.dev/vulnerability/" + e.ID
+	v.Aliases = append(v.Aliases, e.Aliases...)
+	v.Description = e.Summary
+	v.Detail = e.Details
+	if !e.Modified.IsZero() {
+		v.Updated = timestamppb.New(e.Modified)
+	}
+	if e.Published != nil {
+		v.Published = timestamppb.New(*e.Published)
+	}
+
+	for _, s := range e.Severity {
+		method, ok := cvssMethods[s.Type]
+		if !ok {
+			method = "other"
+		}
+		if strings.HasPrefix(s.Score, "CVSS:3.1/") {
+			method = "CVSSv31"
+		}
+		v.Ratings = append(v.Ratings, &sbom.Rating{
+			SourceName: "OSV",
+			Method:     method,
+			Vector:     s.Score,
+		})
+	}
+
+	for _, r := range e.References {
+		v.Advisories = append(v.Advisories, &sbom.ExternalReference{
+			Url:  r.URL,
+			Type: strings.ToLower(r.Type),
+		})
+	}
+	return v
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package osv matches the nodes of protobom documents against a local copy
+// of a vulnerability database in the Open Source Vulnerability format
+// (https://ossf.github.io/osv-schema/).
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Range types defined in the OSV schema
+const (
+	RangeSemVer    = "SEMVER"
+	RangeEcosystem = "ECOSYSTEM"
+	RangeGit       = "GIT"
+)
+
+// Entry is an OSV vulnerability record
+type Entry struct {
+	SchemaVersion string      `json:"schema_version,omitempty"`
+	ID            string      `json:"id"`
+	Modified      time.Time   `json:"modified"`
+	Published     *time.Time  `json:"published,omitempty"`
+	Withdrawn     *time.Time  `json:"withdrawn,omitempty"`
+	Aliases       []string    `json:"aliases,omitempty"`
+	Related       []string    `json:"related,omitempty"`
+	Summary       string      `json:"summary,omitempty"`
+	Details       string      `json:"details,omitempty"`
+	Severity      []Severity  `json:"severity,omitempty"`
+	Affected      []Affected  `json:"affected,omitempty"`
+	References    []Reference `json:"references,omitempty"`
+}
+
+// Severity is a severity score of a vulnerability, such as a CVSS vector
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected lists the affected versions of a package
+type Affected struct {
+	Package  Package    `json:"package"`
+	Severity []Severity `json:"severity,omitempty"`
+	Ranges   []Range    `json:"ranges,omitempty"`
+	Versions []string   `json:"versions,omitempty"`
+}
+
+// Package identifies a package in an ecosystem
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range is a set of events marking the versions where a vulnerability was
+// introduced and fixed.
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Event is a version transition in a range. Only one field is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference is a link to more information about the vulnerability
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Database is an in-memory index of OSV entries by ecosystem and package
+type Database struct {
+	entries  []*Entry
+	packages map[string][]*Entry
+}
+
+// NewDatabase returns an empty database
+func NewDatabase() *Database {
+	return &Database{
+		entries:  []*Entry{},
+		packages: map[string][]*Entry{},
+	}
+}
+
+// Load reads an OSV database from a directory of JSON files or from a zip
+// archive like the ones published at https://osv-vulnerabilities.storage.googleapis.com
+func Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening OSV database: %w", err)
+	}
+
+	db := NewDatabase()
+	if info.IsDir() {
+		err = db.LoadDirectory(path)
+	} else {
+		err = db.LoadZip(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// LoadDirectory adds the entries in all JSON files under a directory
+func (db *Database) LoadDirectory(path string) error {
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".json") {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("opening OSV entry: %w", err)
+		}
+		defer f.Close()
+		if err := db.Read(f); err != nil {
+			return fmt.Errorf("reading %s: %w", p, err)
+		}
+		return nil
+	})
+}
+
+// LoadZip adds the entries in all JSON files in a zip archive
+func (db *Database) LoadZip(path string) error {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("opening OSV archive: %w", err)
+	}
+	defer z.Close()
+
+	for _, zf := range z.File {
+		if zf.FileInfo().IsDir() || !strings.HasSuffix(zf.Name, ".json") {
+			continue
+		}
+		f, err := zf.Open()
+		if err != nil {
+			return fmt.Errorf("opening %s in archive: %w", zf.Name, err)
+		}
+		err = db.Read(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", zf.Name, err)
+		}
+	}
+	return nil
+}
+
+// Read decodes an OSV entry from a stream and adds it to the database
+func (db *Database) Read(r io.Reader) error {
+	entry := &Entry{}
+	if err := json.NewDecoder(r).Decode(entry); err != nil {
+		return fmt.Errorf("decoding OSV entry: %w", err)
+	}
+	if entry.ID == "" {
+		return fmt.Errorf("OSV entry has no id")
+	}
+	db.Add(entry)
+	return nil
+}
+
+// Add indexes an entry in the database. Withdrawn entries are ignored.
+func (db *Database) Add(entry *Entry) {
+	if entry.Withdrawn != nil {
+		return
+	}
+	db.entries = append(db.entries, entry)
+	seen := map[string]struct{}{}
+	for _, a := range entry.Affected {
+		key := packageKey(baseEcosystem(a.Package.Ecosystem), a.Package.Name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		db.packages[key] = append(db.packages[key], entry)
+	}
+}
+
+// Len returns the number of entries in the database
+func (db *Database) Len() int {
+	return len(db.entries)
+}
+
+// lookup returns the entries affecting a package
+func (db *Database) lookup(ecosystem, name string) []*Entry {
+	return db.packages[packageKey(ecosystem, name)]
+}
@@ -0,0 +1,458 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package osv
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// compareFunc compares two versions returning a negative number when a is
+// lower than b, zero when they are equal and a positive number otherwise.
+type compareFunc func(a, b string) int
+
+// compareInts compares two integers
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareNumeric compares two strings of digits of any length
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return compareInts(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// splitDigits splits a string in alternating runs of digits and non digits
+func splitDigits(s string) []string {
+	ret := []string{}
+	start := 0
+	for i := 1; i <= len(s); i++ {
+		if i == len(s) || isDigit(s[i]) != isDigit(s[i-1]) {
+			ret = append(ret, s[start:i])
+			start = i
+		}
+	}
+	return ret
+}
+
+// compareGeneric compares versions of ecosystems without a specific
+// algorithm. Runs of digits are compared numerically and everything else
+// lexically.
+func compareGeneric(a, b string) int {
+	pa := splitDigits(a)
+	pb := splitDigits(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		var c int
+		if isDigit(pa[i][0]) && isDigit(pb[i][0]) {
+			c = compareNumeric(pa[i], pb[i])
+		} else {
+			c = strings.Compare(pa[i], pb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(pa), len(pb))
+}
+
+// compareSemver compares semantic versions (https://semver.org). A leading
+// "v" is ignored and missing minor or patch numbers are treated as zero.
+func compareSemver(a, b string) int {
+	a = strings.TrimPrefix(a, "v")
+	b = strings.TrimPrefix(b, "v")
+	a, _, _ = strings.Cut(a, "+")
+	b, _, _ = strings.Cut(b, "+")
+	coreA, preA, hasPreA := strings.Cut(a, "-")
+	coreB, preB, hasPreB := strings.Cut(b, "-")
+
+	partsA := strings.Split(coreA, ".")
+	partsB := strings.Split(coreB, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		pa, pb := "0", "0"
+		if i < len(partsA) {
+			pa = partsA[i]
+		}
+		if i < len(partsB) {
+			pb = partsB[i]
+		}
+		if c := compareIdentifier(pa, pb); c != 0 {
+			return c
+		}
+	}
+
+	// A version without prerelease is higher than one with it
+	switch {
+	case !hasPreA && !hasPreB:
+		return 0
+	case !hasPreA:
+		return 1
+	case !hasPreB:
+		return -1
+	}
+
+	idsA := strings.Split(preA, ".")
+	idsB := strings.Split(preB, ".")
+	for i := 0; i < len(idsA) && i < len(idsB); i++ {
+		if c := compareIdentifier(idsA[i], idsB[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(idsA), len(idsB))
+}
+
+// compareIdentifier compares semver identifiers. Numeric identifiers are
+// lower than alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	numA := a != "" && strings.Trim(a, "0123456789") == ""
+	numB := b != "" && strings.Trim(b, "0123456789") == ""
+	switch {
+	case numA && numB:
+		return compareNumeric(a, b)
+	case numA:
+		return -1
+	case numB:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// pep440Pattern is the version pattern from PEP 440 appendix B
+var pep440Pattern = regexp.MustCompile(`^\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?P<pre>[-_\.]?(?P<pre_l>alpha|beta|preview|pre|a|b|c|rc)[-_\.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_\.]?(?P<post_l>post|rev|r)[-_\.]?(?P<post_n2>[0-9]+)?))?` +
+	`(?P<dev>[-_\.]?(?P<dev_l>dev)[-_\.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_\.][a-z0-9]+)*))?\s*$`)
+
+// pep440Version is a parsed Python package version
+type pep440Version struct {
+	epoch   int
+	release []int
+	// prePhase is -1 for dev-only releases, 0-2 for a, b and rc and 3 for
+	// final releases, so that they sort in that order.
+	prePhase int
+	pre      int
+	post     int
+	dev      int
+	local    []string
+}
+
+// parsePEP440 parses a version string, returning false if it is invalid
+func parsePEP440(s string) (*pep440Version, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return nil, false
+	}
+	group := func(name string) string {
+		return m[pep440Pattern.SubexpIndex(name)]
+	}
+	atoi := func(s string) int {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+
+	v := &pep440Version{
+		epoch:    atoi(group("epoch")),
+		release:  []int{},
+		prePhase: 3,
+		post:     -1,
+		dev:      math.MaxInt,
+		local:    []string{},
+	}
+
+	for _, n := range strings.Split(group("release"), ".") {
+		v.release = append(v.release, atoi(n))
+	}
+	// Trailing zeros are not significant: 1.0 == 1.0.0
+	for len(v.release) > 1 && v.release[len(v.release)-1] == 0 {
+		v.release = v.release[:len(v.release)-1]
+	}
+
+	switch group("pre_l") {
+	case "a", "alpha":
+		v.prePhase = 0
+	case "b", "beta":
+		v.prePhase = 1
+	case "c", "rc", "pre", "preview":
+		v.prePhase = 2
+	}
+	v.pre = atoi(group("pre_n"))
+
+	if group("post") != "" {
+		v.post = atoi(group("post_n1") + group("post_n2"))
+	}
+
+	if group("dev") != "" {
+		v.dev = atoi(group("dev_n"))
+		if group("pre") == "" && group("post") == "" {
+			v.prePhase = -1
+		}
+	}
+
+	if local := group("local"); local != "" {
+		v.local = strings.FieldsFunc(local, func(r rune) bool {
+			return r == '.' || r == '-' || r == '_'
+		})
+	}
+	return v, true
+}
+
+// comparePEP440 compares Python package versions as defined in PEP 440.
+// Versions that do not follow the spec are compared with compareGeneric.
+func comparePEP440(a, b string) int {
+	va, okA := parsePEP440(a)
+	vb, okB := parsePEP440(b)
+	if !okA || !okB {
+		return compareGeneric(a, b)
+	}
+
+	if c := compareInts(va.epoch, vb.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(va.release) || i < len(vb.release); i++ {
+		ra, rb := 0, 0
+		if i < len(va.release) {
+			ra = va.release[i]
+		}
+		if i < len(vb.release) {
+			rb = vb.release[i]
+		}
+		if c := compareInts(ra, rb); c != 0 {
+			return c
+		}
+	}
+	for _, pair := range [][2]int{
+		{va.prePhase, vb.prePhase}, {va.pre, vb.pre}, {va.post, vb.post}, {va.dev, vb.dev},
+	} {
+		if c := compareInts(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+
+	// Local versions sort after the public one. Numeric segments are
+	// higher than alphanumeric ones.
+	for i := 0; i < len(va.local) && i < len(vb.local); i++ {
+		if c := compareLocalSegment(va.local[i], vb.local[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(va.local), len(vb.local))
+}
+
+// compareLocalSegment compares segments of PEP 440 local versions, the
+// reverse of semver identifiers: numbers are higher than strings.
+func compareLocalSegment(a, b string) int {
+	numA := strings.Trim(a, "0123456789") == ""
+	numB := strings.Trim(b, "0123456789") == ""
+	if numA != numB {
+		return -compareIdentifier(a, b)
+	}
+	return compareIdentifier(a, b)
+}
+
+// compareDebian compares Debian package versions ([epoch:]upstream[-revision])
+// following the algorithm in dpkg.
+func compareDebian(a, b string) int {
+	epochA, upstreamA, revisionA := splitDebian(a)
+	epochB, upstreamB, revisionB := splitDebian(b)
+	if c := compareNumeric(epochA, epochB); c != 0 {
+		return c
+	}
+	if c := compareDebianPart(upstreamA, upstreamB); c != 0 {
+		return c
+	}
+	return compareDebianPart(revisionA, revisionB)
+}
+
+// splitDebian splits a Debian version in epoch, upstream and revision
+func splitDebian(v string) (epoch, upstream, revision string) {
+	epoch = "0"
+	if e, rest, ok := strings.Cut(v, ":"); ok {
+		epoch = e
+		v = rest
+	}
+	if i := strings.LastIndex(v, "-"); i != -1 {
+		return epoch, v[:i], v[i+1:]
+	}
+	return epoch, v, ""
+}
+
+// debianOrder returns the sort weight of a character in the non-digit parts
+// of a Debian version: tildes sort before anything, even the end of the
+// string, and letters sort before other characters.
+func debianOrder(s string, i int) int {
+	switch {
+	case i >= len(s):
+		return 0
+	case isDigit(s[i]):
+		return 0
+	case isLetter(s[i]):
+		return int(s[i])
+	case s[i] == '~':
+		return -1
+	default:
+		return int(s[i]) + 256
+	}
+}
+
+// compareDebianPart is dpkg's verrevcmp
+func compareDebianPart(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac := debianOrder(a, i)
+			bc := debianOrder(b, j)
+			if ac != bc {
+				return compareInts(ac, bc)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = compareInts(int(a[i]), int(b[j]))
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+// apkSuffixes ranks the apk version suffixes. Versions without a suffix
+// sort between the pre-release and the post-release ones.
+var apkSuffixes = map[string]int{
+	"alpha": 0,
+	"beta":  1,
+	"pre":   2,
+	"rc":    3,
+	"":      4,
+	"cvs":   5,
+	"svn":   6,
+	"git":   7,
+	"hg":    8,
+	"p":     9,
+}
+
+// apkVersion is a parsed Alpine package version
+type apkVersion struct {
+	numbers  []string
+	letter   string
+	suffixes [][2]string
+	revision string
+}
+
+// parseAPK parses an apk version: numbers separated by dots, an optional
+// letter, any number of _suffixN and an optional -rN revision.
+func parseAPK(v string) *apkVersion {
+	ret := &apkVersion{numbers: []string{}, suffixes: [][2]string{}, revision: "0"}
+	if i := strings.LastIndex(v, "-r"); i != -1 {
+		ret.revision = v[i+2:]
+		v = v[:i]
+	}
+
+	rest := v
+	if i := strings.Index(v, "_"); i != -1 {
+		rest = v[:i]
+		for _, s := range strings.Split(v[i+1:], "_") {
+			j := len(s)
+			for j > 0 && isDigit(s[j-1]) {
+				j--
+			}
+			ret.suffixes = append(ret.suffixes, [2]string{s[:j], s[j:]})
+		}
+	}
+
+	if rest != "" && isLetter(rest[len(rest)-1]) {
+		ret.letter = rest[len(rest)-1:]
+		rest = rest[:len(rest)-1]
+	}
+	ret.numbers = strings.Split(rest, ".")
+	return ret
+}
+
+// compareAPK compares Alpine package versions
+func compareAPK(a, b string) int {
+	va := parseAPK(a)
+	vb := parseAPK(b)
+
+	for i := 0; i < len(va.numbers) && i < len(vb.numbers); i++ {
+		if c := compareNumeric(va.numbers[i], vb.numbers[i]); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(len(va.numbers), len(vb.numbers)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(va.letter, vb.letter); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(va.suffixes) || i < len(vb.suffixes); i++ {
+		sa, sb := [2]string{"", "0"}, [2]string{"", "0"}
+		if i < len(va.suffixes) {
+			sa = va.suffixes[i]
+		}
+		if i < len(vb.suffixes) {
+			sb = vb.suffixes[i]
+		}
+		ra, okA := apkSuffixes[sa[0]]
+		rb, okB := apkSuffixes[sb[0]]
+		if !okA || !okB {
+			if c := strings.Compare(sa[0], sb[0]); c != 0 {
+				return c
+			}
+		} else if c := compareInts(ra, rb); c != 0 {
+			return c
+		}
+		if c := compareNumeric(sa[1], sb[1]); c != 0 {
+			return c
+		}
+	}
+	return compareNumeric(va.revision, vb.revision)
+}
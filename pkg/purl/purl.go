@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package purl implements parsing, building and matching of package URLs as
+// defined in https://github.com/package-url/purl-spec
+package purl
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PackageURL is a parsed package URL
+type PackageURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// New returns a package URL of the specified type, namespace, name and version
+func New(purlType, namespace, name, version string, qualifiers map[string]string) *PackageURL {
+	if qualifiers == nil {
+		qualifiers = map[string]string{}
+	}
+	return &PackageURL{
+		Type:       purlType,
+		Namespace:  namespace,
+		Name:       name,
+		Version:    version,
+		Qualifiers: qualifiers,
+	}
+}
+
+// Parse reads a package URL string
+func Parse(s string) (*PackageURL, error) {
+	if !strings.HasPrefix(s, "pkg:") {
+		return nil, fmt.Errorf("package url %q does not start with pkg:", s)
+	}
+	rest := strings.TrimLeft(strings.TrimPrefix(s, "pkg:"), "/")
+
+	p := &PackageURL{Qualifiers: map[string]string{}}
+
+	if i := strings.LastIndex(rest, "#"); i != -1 {
+		subpath, err := url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("decoding subpath: %w", err)
+		}
+		p.Subpath = strings.Trim(subpath, "/")
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, "?"); i != -1 {
+		for _, pair := range strings.Split(rest[i+1:], "&") {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || k == "" {
+				continue
+			}
+			value, err := url.PathUnescape(v)
+			if err != nil {
+				return nil, fmt.Errorf("decoding qualifier %s: %w", k, err)
+			}
+			p.Qualifiers[strings.ToLower(k)] = value
+		}
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, "@"); i != -1 && i > strings.LastIndex(rest, "/") {
+		version, err := url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("decoding version: %w", err)
+		}
+		p.Version = version
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("package url %q has no type or name", s)
+	}
+	p.Type = strings.ToLower(parts[0])
+
+	name, err := url.PathUnescape(parts[len(parts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("decoding name: %w", err)
+	}
+	p.Name = name
+
+	ns := []string{}
+	for _, seg := range parts[1 : len(parts)-1] {
+		seg, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, fmt.Errorf("decoding namespace: %w", err)
+		}
+		ns = append(ns, seg)
+	}
+	p.Namespace = strings.Join(ns, "/")
+
+	if p.Name == "" {
+		return nil, fmt.Errorf("package url %q has no name", s)
+	}
+	return p, nil
+}
+
+// String returns the canonical string form of the package URL
+func (p *PackageURL) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("pkg:")
+	sb.WriteString(strings.ToLower(p.Type))
+	sb.WriteString("/")
+	if p.Namespace != "" {
+		for _, seg := range strings.Split(p.Namespace, "/") {
+			sb.WriteString(escape(seg))
+			sb.WriteString("/")
+		}
+	}
+	sb.WriteString(escape(p.Name))
+	if p.Version != "" {
+		sb.WriteString("@")
+		sb.WriteString(escape(p.Version))
+	}
+
+	if len(p.Qualifiers) > 0 {
+		keys := []string{}
+		for k, v := range p.Qualifiers {
+			if v != "" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		pairs := []string{}
+		for _, k := range keys {
+			pairs = append(pairs, strings.ToLower(k)+"="+escape(p.Qualifiers[k]))
+		}
+		if len(pairs) > 0 {
+			sb.WriteString("?")
+			sb.WriteString(strings.Join(pairs, "&"))
+		}
+	}
+
+	if p.Subpath != "" {
+		sb.WriteString("#")
+		sb.WriteString(p.Subpath)
+	}
+	return sb.String()
+}
+
+// escape percent-encodes a purl component. The at sign is encoded as it
+// separates the version, colons are left as is to keep digests readable.
+func escape(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+}
+
+// Matches returns true if other refers to the same package as p. Empty
+// fields in p match any value in other, so a package URL without a version
+// matches all versions of the package. Qualifiers in p must be present with
+// the same value in other.
+func (p *PackageURL) Matches(other *PackageURL) bool {
+	if other == nil {
+		return false
+	}
+	if !strings.EqualFold(p.Type, other.Type) ||
+		!strings.EqualFold(p.Namespace, other.Namespace) ||
+		!strings.EqualFold(p.Name, other.Name) {
+		return false
+	}
+	if p.Version != "" && p.Version != other.Version {
+		return false
+	}
+	for k, v := range p.Qualifiers {
+		if other.Qualifiers[k] != v {
+			return false
+		}
+	}
+	if p.Subpath != "" && p.Subpath != other.Subpath {
+		return false
+	}
+	return true
+}
+
+// Match parses two package URL strings and returns true if the first one
+// matches the second. Invalid package URLs never match.
+func Match(pattern, s string) bool {
+	p, err := Parse(pattern)
+	if err != nil {
+		return false
+	}
+	other, err := Parse(s)
+	if err != nil {
+		return false
+	}
+	return p.Matches(other)
+}
@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// BundleFunc receives each document of a bundle with the path of its
+// entry or the error parsing it. Returning an error stops the iteration.
+type BundleFunc func(path string, doc *sbom.Document, err error) error
+
+// bundleEntry is the data of an entry read from a bundle
+type bundleEntry struct {
+	path string
+	data []byte
+}
+
+// bundleIterator sends the entries of a bundle
+type bundleIterator func(send func(*bundleEntry) error) error
+
+// ParseBundleFile parses all the documents in a bundle file, see
+// ParseBundle. Zip files are read without loading them in memory.
+func (p *Parser) ParseBundleFile(ctx context.Context, path string, concurrency int, fn BundleFunc) error {
+	f, err := p.impl.OpenDocumentFile(path)
+	if err != nil {
+		return fmt.Errorf("opening bundle: %w", err)
+	}
+	defer f.Close()
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(f, header); err == nil && isZip(header) {
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("reading bundle size: %w", err)
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			return fmt.Errorf("opening zip bundle: %w", err)
+		}
+		return p.parseBundle(ctx, zipIterator(zr), concurrency, fn)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding bundle: %w", err)
+	}
+	return p.ParseBundle(ctx, f, concurrency, fn)
+}
+
+// ParseBundle parses all the documents in a tar or zip archive or in a
+// stream of JSON documents such as a JSON-lines file. The bundle may be
+// compressed. The format of each entry is detected, entries that are not
+// SBOMs are passed to fn with their error. Up to concurrency entries are
+// parsed at once, all the CPUs are used if it is not positive. Documents
+// are passed to fn one at a time but not in the order of the bundle.
+// Entries of JSON streams are named after their position, starting at #1.
+func (p *Parser) ParseBundle(ctx context.Context, r io.Reader, concurrency int, fn BundleFunc) error {
+	r, _, err := decompress(r)
+	if err != nil {
+		return fmt.Errorf("decompressing bundle: %w", err)
+	}
+	br := bufio.NewReader(r)
+	header, err := br.Peek(262)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading bundle header: %w", err)
+	}
+
+	var iterate bundleIterator
+	switch {
+	case isZip(header):
+		// Zip archives need random access
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return fmt.Errorf("reading zip bundle: %w", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("opening zip bundle: %w", err)
+		}
+		iterate = zipIterator(zr)
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		iterate = tarIterator(tar.NewReader(br))
+	default:
+		iterate = jsonIterator(br)
+	}
+	return p.parseBundle(ctx, iterate, concurrency, fn)
+}
+
+// parseBundle parses the entries of a bundle in a pool of workers
+func (p *Parser) parseBundle(ctx context.Context, iterate bundleIterator, concurrency int, fn BundleFunc) error {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		path string
+		doc  *sbom.Document
+		err  error
+	}
+	entries := make(chan *bundleEntry)
+	results := make(chan *result)
+
+	iterErr := make(chan error, 1)
+	go func() {
+		defer close(entries)
+		iterErr <- iterate(func(e *bundleEntry) error {
+			select {
+			case entries <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range entries {
+				doc, err := p.ParseReader(bytes.NewReader(e.data))
+				select {
+				case results <- &result{path: e.path, doc: doc, err: err}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var fnErr error
+	for res := range results {
+		if fnErr != nil {
+			continue
+		}
+		if err := fn(res.path, res.doc, res.err); err != nil {
+			fnErr = err
+			cancel()
+		}
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+	if err := <-iterErr; err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+	return ctx.Err()
+}
+
+// isZip returns true if the header is the start of a zip archive
+func isZip(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06"))
+}
+
+// tarIterator sends the regular files in a tar archive
+func tarIterator(tr *tar.Reader) bundleIterator {
+	return func(send func(*bundleEntry) error) error {
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading tar entry: %w", err)
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", hdr.Name, err)
+			}
+			if err := send(&bundleEntry{path: hdr.Name, data: data}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// zipIterator sends the files in a zip archive
+func zipIterator(zr *zip.Reader) bundleIterator {
+	return func(send func(*bundleEntry) error) error {
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			f, err := zf.Open()
+			if err != nil {
+				return fmt.Errorf("opening %s: %w", zf.Name, err)
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", zf.Name, err)
+			}
+			if err := send(&bundleEntry{path: zf.Name, data: data}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// jsonIterator sends the values in a stream of JSON documents
+func jsonIterator(r io.Reader) bundleIterator {
+	return func(send func(*bundleEntry) error) error {
+		dec := json.NewDecoder(r)
+		for i := 1; ; i++ {
+			raw := json.RawMessage{}
+			if err := dec.Decode(&raw); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("decoding document #%d: %w", i, err)
+			}
+			if err := send(&bundleEntry{path: fmt.Sprintf("#%d", i), data: raw}); err != nil {
+				return err
+			}
+		}
+	}
+}
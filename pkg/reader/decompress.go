@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Compression algorithms detected in the input
+const (
+	CompressionNone  = ""
+	CompressionGzip  = "gzip"
+	CompressionBzip2 = "bzip2"
+	CompressionZstd  = "zstd"
+	CompressionXZ    = "xz"
+)
+
+// compressionMagic are the bytes starting the streams of each algorithm
+var compressionMagic = []struct {
+	algorithm string
+	magic     []byte
+}{
+	{CompressionGzip, []byte{0x1f, 0x8b}},
+	{CompressionBzip2, []byte("BZh")},
+	{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{CompressionXZ, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// ErrUnsupportedCompression is returned when the input is compressed with
+// an algorithm that can't be decompressed.
+var ErrUnsupportedCompression = errors.New("unsupported compression")
+
+// DetectCompression returns the compression algorithm of a stream from its
+// first bytes, CompressionNone if it is not compressed.
+func DetectCompression(header []byte) string {
+	for _, c := range compressionMagic {
+		if bytes.HasPrefix(header, c.magic) {
+			return c.algorithm
+		}
+	}
+	return CompressionNone
+}
+
+// decompress returns a reader of the decompressed data of r and the
+// algorithm it was compressed with. Uncompressed data is returned as is.
+// Only gzip and bzip2 can be decompressed, the other algorithms are
+// detected to return a clear error.
+func decompress(r io.Reader) (io.Reader, string, error) {
+	br := bufio.NewReader(r)
+	// Peek returns the available bytes when the stream is shorter
+	header, err := br.Peek(6)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", fmt.Errorf("reading stream header: %w", err)
+	}
+
+	algorithm := DetectCompression(header)
+	switch algorithm {
+	case CompressionNone:
+		return br, algorithm, nil
+	case CompressionGzip:
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, algorithm, fmt.Errorf("opening gzip stream: %w", err)
+		}
+		return zr, algorithm, nil
+	case CompressionBzip2:
+		return bzip2.NewReader(br), algorithm, nil
+	default:
+		return nil, algorithm, fmt.Errorf("%w: %s compressed documents can't be read", ErrUnsupportedCompression, algorithm)
+	}
+}
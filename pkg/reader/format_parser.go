@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/onesbom/onesbom/pkg/formats/spdx"
 	spdx23 "github.com/onesbom/onesbom/pkg/formats/spdx/v23"
@@ -25,59 +26,313 @@ type FormatParserSPDX23 struct{}
 type FormatParserCDX14 struct{}
 
 func (gfp *FormatParserSPDX23) Parse(opts *options.Options, r io.Reader) (*sbom.Document, error) {
-	spdxDoc := &spdx23.Document{}
-	dc := json.NewDecoder(r)
-	if err := dc.Decode(spdxDoc); err != nil {
-		return nil, fmt.Errorf("decoding SPDX 2.3 document: %w", err)
-	}
+	return gfp.ParseStream(opts, r, nil)
+}
 
+// ParseStream decodes an SPDX 2.3 JSON document token by token. Packages,
+// files and relationships are converted and handed to the handlers one at
+// a time so the document never needs to be fully loaded in memory.
+func (gfp *FormatParserSPDX23) ParseStream(opts *options.Options, r io.Reader, handlers *StreamHandlers) (*sbom.Document, error) {
+	if handlers == nil {
+		handlers = &StreamHandlers{}
+	}
 	bom := &sbom.Document{
 		Metadata: &sbom.Metadata{
-			Id:      spdxDoc.ID,
 			Version: "0",
-			Name:    spdxDoc.Name,
-			// Date:    &timestamppb.New(spdxDoc.CreationInfo.Created), // bug in onesbom
 			Tools:   []*sbom.Tool{},
 			Authors: []*sbom.Person{},
 		},
+		RootElements: []string{},
 	}
 
-	// Add the top level components
-	bom.RootElements = spdxDoc.DocumentDescribes
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		if handlers.Metadata != nil {
+			return handlers.Metadata(bom.Metadata)
+		}
+		return nil
+	}
+	emitNode := func(n *sbom.Node) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if !opts.DiscardElements {
+			bom.Nodes = append(bom.Nodes, n)
+		}
+		if handlers.Node != nil {
+			return handlers.Node(n)
+		}
+		return nil
+	}
 
-	// Assign the document metadata
+	// IDs of the files dropped with the SkipFiles option
+	skipped := map[string]struct{}{}
 
-	// Range the packages and add them to the doc
-	for i := range spdxDoc.Packages {
-		p, err := package23ToNode(&spdxDoc.Packages[i])
+	dc := json.NewDecoder(r)
+	if err := expectDelim(dc, '{'); err != nil {
+		return nil, fmt.Errorf("decoding SPDX 2.3 document: %w", err)
+	}
+	for dc.More() {
+		t, err := dc.Token()
 		if err != nil {
-			return nil, fmt.Errorf("rendering node from spdx package: %w", err)
+			return nil, fmt.Errorf("decoding SPDX 2.3 document: %w", err)
 		}
+		key, _ := t.(string)
 
-		bom.Nodes = append(bom.Nodes, p)
+		switch key {
+		case "SPDXID":
+			if err := dc.Decode(&bom.Metadata.Id); err != nil {
+				return nil, fmt.Errorf("decoding document ID: %w", err)
+			}
+		case "name":
+			if err := dc.Decode(&bom.Metadata.Name); err != nil {
+				return nil, fmt.Errorf("decoding document name: %w", err)
+			}
+		case "creationInfo":
+			ci := &spdx23.CreationInfo{}
+			if err := dc.Decode(ci); err != nil {
+				return nil, fmt.Errorf("decoding creation info: %w", err)
+			}
+			if err := addCreationInfo(opts, bom.Metadata, ci); err != nil {
+				return nil, err
+			}
+		case "documentDescribes":
+			ids := []string{}
+			if err := dc.Decode(&ids); err != nil {
+				return nil, fmt.Errorf("decoding described elements: %w", err)
+			}
+			// Add the top level components
+			for _, id := range ids {
+				addRootElement(bom, strings.TrimPrefix(id, spdx23.IDPrefix))
+			}
+		case "packages":
+			err = decodeArray(dc, func() error {
+				spdxPackage := &spdx23Package{}
+				if err := dc.Decode(spdxPackage); err != nil {
+					return fmt.Errorf("decoding package: %w", err)
+				}
+				p, err := package23ToNode(opts, spdxPackage)
+				if err != nil {
+					return fmt.Errorf("rendering node from spdx package: %w", err)
+				}
+				if p == nil {
+					return nil
+				}
+				return emitNode(p)
+			})
+		case "files":
+			err = decodeArray(dc, func() error {
+				spdxFile := &spdx23.File{}
+				if err := dc.Decode(spdxFile); err != nil {
+					return fmt.Errorf("decoding file: %w", err)
+				}
+				if opts.SkipFiles {
+					skipped[strings.TrimPrefix(spdxFile.ID, spdx23.IDPrefix)] = struct{}{}
+					return nil
+				}
+				f, err := file23ToNode(opts, spdxFile)
+				if err != nil {
+					return fmt.Errorf("creating node from spdx file: %w", err)
+				}
+				if f == nil {
+					return nil
+				}
+				return emitNode(f)
+			})
+		case "relationships":
+			if opts.SkipRelationships {
+				err = skipValue(dc)
+				break
+			}
+			err = decodeArray(dc, func() error {
+				rel := &spdx23.Relationship{}
+				if err := dc.Decode(rel); err != nil {
+					return fmt.Errorf("decoding relationship: %w", err)
+				}
+				// The elements described by the document are its roots
+				if id := describedElement(rel); id != "" {
+					addRootElement(bom, id)
+					return nil
+				}
+				e, err := relationship23ToEdge(opts, rel)
+				if err != nil {
+					return fmt.Errorf("creating edge from spdx relationship: %w", err)
+				}
+				if e == nil {
+					return nil
+				}
+				if e = pruneEdge(e, skipped); e == nil {
+					return nil
+				}
+				if err := start(); err != nil {
+					return err
+				}
+				if !opts.DiscardElements {
+					bom.Edges = append(bom.Edges, e)
+				}
+				if handlers.Edge != nil {
+					return handlers.Edge(e)
+				}
+				return nil
+			})
+		default:
+			err = skipValue(dc)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dc, '}'); err != nil {
+		return nil, fmt.Errorf("decoding SPDX 2.3 document: %w", err)
 	}
 
-	for i := range spdxDoc.Files {
-		f, err := file23ToNode(&spdxDoc.Files[i])
-		if err != nil {
-			return nil, fmt.Errorf("creating node from spdx file: %w", nil)
+	// Documents without elements still report their metadata
+	if err := start(); err != nil {
+		return nil, err
+	}
+
+	// Files read after the relationships are dropped from the edges now
+	if len(skipped) > 0 && len(bom.Edges) > 0 {
+		edges := []*sbom.Edge{}
+		for _, e := range bom.Edges {
+			if e = pruneEdge(e, skipped); e != nil {
+				edges = append(edges, e)
+			}
+		}
+		bom.Edges = edges
+	}
+	return bom, nil
+}
+
+// spdx23Package reads the dates of a package, onesbom decodes them from
+// fields with the wrong names.
+type spdx23Package struct {
+	spdx23.Package
+	ReleaseDate    string `json:"releaseDate"`
+	BuildDate      string `json:"builtDate"`
+	ValidUntilDate string `json:"validUntilDate"`
+}
+
+// pruneEdge removes the dropped nodes from an edge. It returns nil if no
+// node is left at either end.
+func pruneEdge(e *sbom.Edge, dropped map[string]struct{}) *sbom.Edge {
+	if len(dropped) == 0 {
+		return e
+	}
+	if _, ok := dropped[e.From]; ok {
+		return nil
+	}
+	to := []string{}
+	for _, id := range e.To {
+		if _, ok := dropped[id]; !ok {
+			to = append(to, id)
 		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	e.To = to
+	return e
+}
 
-		bom.Nodes = append(bom.Nodes, f)
+// parseDate parses an SPDX date, reporting a problem if it is invalid.
+// It returns nil if the date is empty or invalid.
+func parseDate(opts *options.Options, field, value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
 	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, opts.Problem("parsing %s: %w", field, err)
+	}
+	return timestamppb.New(t), nil
+}
 
-	for i := range spdxDoc.Relationships {
-		e, err := relationship23ToEdge(&spdxDoc.Relationships[i])
-		if err != nil {
-			return nil, fmt.Errorf("")
+// addCreationInfo adds the creation date and creators to the metadata
+func addCreationInfo(opts *options.Options, md *sbom.Metadata, ci *spdx23.CreationInfo) error {
+	// onesbom reads the creation date as a string so we parse it here
+	created, err := parseDate(opts, "document creation date", ci.Created)
+	if err != nil {
+		return err
+	}
+	md.Date = created
+
+	for _, creator := range ci.Creators {
+		if tool := toolFromCreator(creator); tool != nil {
+			md.Tools = append(md.Tools, tool)
+			continue
+		}
+		if author := actorToPerson(creator); author != nil {
+			md.Authors = append(md.Authors, author)
 		}
-		bom.Edges = append(bom.Edges, e)
 	}
+	return nil
+}
 
-	return bom, nil
+// expectDelim reads the next token, which must be the delimiter
+func expectDelim(dc *json.Decoder, delim json.Delim) error {
+	t, err := dc.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %s but found %v", delim, t)
+	}
+	return nil
 }
 
-func file23ToNode(spdxFile *spdx23.File) (*sbom.Node, error) {
+// decodeArray calls decode for each element of a JSON array. Null is
+// read as an empty array.
+func decodeArray(dc *json.Decoder, decode func() error) error {
+	t, err := dc.Token()
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	if d, ok := t.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("expected array but found %v", t)
+	}
+	for dc.More() {
+		if err := decode(); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dc, ']')
+}
+
+// skipValue reads the next value without decoding it
+func skipValue(dc *json.Decoder) error {
+	depth := 0
+	for {
+		t, err := dc.Token()
+		if err != nil {
+			return err
+		}
+		if d, ok := t.(json.Delim); ok {
+			if d == '{' || d == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// file23ToNode converts an SPDX file to a node. Files without an ID are
+// dropped, it returns nil for them when not in strict mode.
+func file23ToNode(opts *options.Options, spdxFile *spdx23.File) (*sbom.Node, error) {
+	if spdxFile.ID == "" {
+		return nil, opts.Problem("file %q has no SPDX ID", spdxFile.Name)
+	}
 	f := &sbom.Node{
 		Id:                 strings.TrimPrefix(spdxFile.ID, spdx23.IDPrefix),
 		Type:               1,
@@ -118,7 +373,12 @@ func file23ToNode(spdxFile *spdx23.File) (*sbom.Node, error) {
 	return f, nil
 }
 
-func package23ToNode(spdxPackage *spdx23.Package) (*sbom.Node, error) {
+// package23ToNode converts an SPDX package to a node. Packages without an
+// ID are dropped, it returns nil for them when not in strict mode.
+func package23ToNode(opts *options.Options, spdxPackage *spdx23Package) (*sbom.Node, error) {
+	if spdxPackage.ID == "" {
+		return nil, opts.Problem("package %q has no SPDX ID", spdxPackage.Name)
+	}
 	p := &sbom.Node{
 		Type:               0,
 		Name:               spdxPackage.Name,
@@ -169,26 +429,14 @@ func package23ToNode(spdxPackage *spdx23.Package) (*sbom.Node, error) {
 	}
 
 	if spdxPackage.Supplier != "" {
-		p.Suppliers = []*sbom.Person{}
-		actorType, actorName, actorEmail := spdx.ParseActorString(spdxPackage.Supplier)
-		if actorType != "" {
-			p.Suppliers[0] = &sbom.Person{
-				Name:  actorName,
-				Email: actorEmail,
-				IsOrg: (actorType == "org"),
-			}
+		if supplier := actorToPerson(spdxPackage.Supplier); supplier != nil {
+			p.Suppliers = append(p.Suppliers, supplier)
 		}
 	}
 
 	if spdxPackage.Originator != "" {
-		p.Suppliers = []*sbom.Person{}
-		actorType, actorName, actorEmail := spdx.ParseActorString(spdxPackage.Originator)
-		if actorType != "" {
-			p.Originators[0] = &sbom.Person{
-				Name:  actorName,
-				Email: actorEmail,
-				IsOrg: (actorType == "org"),
-			}
+		if originator := actorToPerson(spdxPackage.Originator); originator != nil {
+			p.Originators = append(p.Originators, originator)
 		}
 	}
 
@@ -201,24 +449,121 @@ func package23ToNode(spdxPackage *spdx23.Package) (*sbom.Node, error) {
 		p.LicenseConcluded = spdxPackage.LicenseConcluded
 	}
 
-	if spdxPackage.ReleaseDate != nil {
-		p.ReleaseDate = timestamppb.New(*spdxPackage.ReleaseDate)
+	var err error
+	for _, date := range []struct {
+		field string
+		value string
+		dest  **timestamppb.Timestamp
+	}{
+		{"release date", spdxPackage.ReleaseDate, &p.ReleaseDate},
+		{"build date", spdxPackage.BuildDate, &p.BuildDate},
+		{"valid until date", spdxPackage.ValidUntilDate, &p.ValidUntilDate},
+	} {
+		*date.dest, err = parseDate(opts, fmt.Sprintf("%s of package %s", date.field, spdxPackage.ID), date.value)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return p, nil
+}
 
-	if spdxPackage.BuildDate != nil {
-		p.BuildDate = timestamppb.New(*spdxPackage.BuildDate)
+// documentID is the SPDX ID of the document itself
+const documentID = spdx23.IDPrefix + "DOCUMENT"
+
+// describedElement returns the ID of the element a relationship says the
+// document describes, or an empty string if it is not a DESCRIBES or
+// DESCRIBED_BY relationship of the document.
+func describedElement(r *spdx23.Relationship) string {
+	switch {
+	case r.Type == "DESCRIBES" && r.Element == documentID:
+		return strings.TrimPrefix(r.Related, spdx23.IDPrefix)
+	case r.Type == "DESCRIBED_BY" && r.Related == documentID:
+		return strings.TrimPrefix(r.Element, spdx23.IDPrefix)
+	default:
+		return ""
 	}
+}
 
-	if spdxPackage.ValidUntilDate != nil {
-		p.ValidUntilDate = timestamppb.New(*spdxPackage.ValidUntilDate)
+// addRootElement adds an element to the document roots unless it is
+// already one. Documents may list their roots in documentDescribes and
+// in relationships.
+func addRootElement(bom *sbom.Document, id string) {
+	for _, root := range bom.RootElements {
+		if root == id {
+			return
+		}
 	}
-	return p, nil
+	bom.RootElements = append(bom.RootElements, id)
 }
 
-func relationship23ToEdge(r *spdx23.Relationship) (*sbom.Edge, error) {
+// relationship23ToEdge converts an SPDX relationship to an edge.
+// Relationships missing an element ID are dropped, it returns nil for them
+// when not in strict mode.
+func relationship23ToEdge(opts *options.Options, r *spdx23.Relationship) (*sbom.Edge, error) {
+	if r.Element == "" || r.Related == "" {
+		return nil, opts.Problem("%s relationship is missing an element ID", r.Type)
+	}
+	if sbom.EdgeTypeFromSPDX(r.Type) == sbom.Edge_UNKNOWN {
+		if err := opts.Problem("unknown relationship type %q from %s", r.Type, r.Element); err != nil {
+			return nil, err
+		}
+	}
 	return &sbom.Edge{
 		Type: sbom.EdgeTypeFromSPDX(r.Type),
 		From: strings.TrimPrefix(r.Element, spdx23.IDPrefix),
 		To:   []string{strings.TrimPrefix(r.Related, spdx23.IDPrefix)},
 	}, nil
 }
+
+// actorToPerson parses an SPDX actor string into a person. It returns nil if
+// the actor is not a person or organization.
+func actorToPerson(actor string) *sbom.Person {
+	actorType, actorName, actorEmail := spdx.ParseActorString(actor)
+	if actorType == "" {
+		return nil
+	}
+	return &sbom.Person{
+		Name:  actorName,
+		Email: actorEmail,
+		IsOrg: (actorType == "org"),
+	}
+}
+
+// toolFromCreator parses an SPDX creator string into a tool if the creator is
+// of type Tool, otherwise it returns nil.
+func toolFromCreator(creator string) *sbom.Tool {
+	creator = strings.TrimSpace(creator)
+	if !strings.HasPrefix(creator, "Tool:") {
+		return nil
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(creator, "Tool:"))
+	tool := &sbom.Tool{Name: name}
+
+	// Tools are written as name-version, the version starts at the first
+	// hyphen followed by a number as both names and versions may have
+	// hyphens. Some generators write "name (version)" instead.
+	if i := strings.LastIndex(name, " ("); i != -1 && strings.HasSuffix(name, ")") {
+		tool.Name = name[:i]
+		tool.Version = strings.TrimSuffix(name[i+2:], ")")
+	} else if i := toolVersionIndex(name); i != -1 {
+		tool.Name = name[:i]
+		tool.Version = name[i+1:]
+	}
+	return tool
+}
+
+// toolVersionIndex returns the index of the hyphen starting the version in
+// a name-version tool string, or -1 if it has no version. Versions start
+// with a digit, optionally prefixed with a v.
+func toolVersionIndex(name string) int {
+	for i := 1; i < len(name)-1; i++ {
+		if name[i] != '-' {
+			continue
+		}
+		v := strings.TrimPrefix(name[i+1:], "v")
+		if v != "" && v[0] >= '0' && v[0] <= '9' {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,267 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	onecdx "github.com/onesbom/onesbom/pkg/formats/cyclonedx"
+	cdx14 "github.com/onesbom/onesbom/pkg/formats/cyclonedx/v14"
+	"github.com/puerco/protobom/pkg/formats/cyclonedx"
+	"github.com/puerco/protobom/pkg/reader/options"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// cdx14Document decodes the timestamps of a CycloneDX 1.4 document as
+// strings so that invalid ones can be reported as problems.
+type cdx14Document struct {
+	cyclonedx.Document
+	Metadata        cdx14Metadata        `json:"metadata"`
+	Vulnerabilities []cdx14Vulnerability `json:"vulnerabilities"`
+}
+
+type cdx14Metadata struct {
+	cdx14.Metadata
+	Timestamp string `json:"timestamp"`
+}
+
+type cdx14Vulnerability struct {
+	cyclonedx.Vulnerability
+	Created   string         `json:"created"`
+	Published string         `json:"published"`
+	Updated   string         `json:"updated"`
+	Analysis  *cdx14Analysis `json:"analysis"`
+}
+
+type cdx14Analysis struct {
+	cyclonedx.Analysis
+	FirstIssued string `json:"firstIssued"`
+	LastUpdated string `json:"lastUpdated"`
+}
+
+func (fp *FormatParserCDX14) Parse(opts *options.Options, r io.Reader) (*sbom.Document, error) {
+	cdxDoc := &cdx14Document{}
+	dc := json.NewDecoder(r)
+	if err := dc.Decode(cdxDoc); err != nil {
+		return nil, fmt.Errorf("decoding CycloneDX 1.4 document: %w", err)
+	}
+
+	bom := &sbom.Document{
+		Metadata: &sbom.Metadata{
+			Id:      cdxDoc.SerialNumber,
+			Version: strconv.Itoa(cdxDoc.Version),
+			Tools:   []*sbom.Tool{},
+			Authors: []*sbom.Person{},
+		},
+		RootElements:    []string{},
+		Nodes:           []*sbom.Node{},
+		Edges:           []*sbom.Edge{},
+		Vulnerabilities: []*sbom.Vulnerability{},
+	}
+
+	date, err := parseDate(opts, "document timestamp", cdxDoc.Metadata.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if date != nil && !date.AsTime().IsZero() {
+		bom.Metadata.Date = date
+	}
+
+	for _, t := range cdxDoc.Metadata.Tools {
+		bom.Metadata.Tools = append(bom.Metadata.Tools, &sbom.Tool{
+			Name:    t.Name,
+			Version: t.Version,
+			Vendor:  t.Vendor,
+		})
+	}
+
+	ids := &cdxIDGenerator{}
+	skipped := map[string]struct{}{}
+
+	// The component described by the document is the root element, the
+	// components of the document are its contents
+	rootID := ""
+	if cdxDoc.Metadata.Component.Name != "" || cdxDoc.Metadata.Component.Ref != "" {
+		root := component14ToNode(&cdxDoc.Metadata.Component, ids)
+		rootID = root.Id
+		bom.Nodes = append(bom.Nodes, root)
+		bom.RootElements = append(bom.RootElements, root.Id)
+		addCDX14Components(opts, bom, root.Id, cdxDoc.Metadata.Component.Components, ids, skipped)
+	}
+
+	addCDX14Components(opts, bom, rootID, cdxDoc.Components, ids, skipped)
+
+	for _, dep := range cdxDoc.Dependencies {
+		if opts.SkipRelationships {
+			break
+		}
+		if dep.Ref == "" {
+			if err := opts.Problem("dependency has no ref"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(dep.DependsOn) == 0 {
+			continue
+		}
+		e := pruneEdge(&sbom.Edge{
+			Type: sbom.Edge_dependsOn,
+			From: dep.Ref,
+			To:   dep.DependsOn,
+		}, skipped)
+		if e != nil {
+			bom.Edges = append(bom.Edges, e)
+		}
+	}
+
+	for i := range cdxDoc.Vulnerabilities {
+		v, err := cdx14VulnerabilityToProto(opts, &cdxDoc.Vulnerabilities[i])
+		if err != nil {
+			return nil, err
+		}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, v)
+	}
+
+	return bom, nil
+}
+
+// cdx14VulnerabilityToProto parses the timestamps of a vulnerability and
+// converts it to protobom. Invalid timestamps are reported as problems.
+func cdx14VulnerabilityToProto(opts *options.Options, v *cdx14Vulnerability) (*sbom.Vulnerability, error) {
+	parse := func(field, value string, dest **time.Time) error {
+		ts, err := parseDate(opts, fmt.Sprintf("%s time of vulnerability %s", field, v.ID), value)
+		if ts != nil {
+			t := ts.AsTime()
+			*dest = &t
+		}
+		return err
+	}
+
+	if err := parse("created", v.Created, &v.Vulnerability.Created); err != nil {
+		return nil, err
+	}
+	if err := parse("published", v.Published, &v.Vulnerability.Published); err != nil {
+		return nil, err
+	}
+	if err := parse("updated", v.Updated, &v.Vulnerability.Updated); err != nil {
+		return nil, err
+	}
+	if v.Analysis != nil {
+		v.Vulnerability.Analysis = &v.Analysis.Analysis
+		if err := parse("first issued", v.Analysis.FirstIssued, &v.Vulnerability.Analysis.FirstIssued); err != nil {
+			return nil, err
+		}
+		if err := parse("last updated", v.Analysis.LastUpdated, &v.Vulnerability.Analysis.LastUpdated); err != nil {
+			return nil, err
+		}
+	}
+	return cyclonedx.VulnerabilityToProto(&v.Vulnerability), nil
+}
+
+// cdxIDGenerator assigns IDs to components which don't have a bom-ref
+type cdxIDGenerator struct {
+	count int
+}
+
+func (g *cdxIDGenerator) next() string {
+	g.count++
+	return fmt.Sprintf("component-%d", g.count)
+}
+
+// addCDX14Components adds a list of components to the document. Nested
+// components are linked to their parent with a contains edge. The refs of
+// the files dropped with the SkipFiles option, and of the components they
+// contain, are recorded in skipped.
+func addCDX14Components(
+	opts *options.Options, bom *sbom.Document, parentID string,
+	components []cdx14.Component, ids *cdxIDGenerator, skipped map[string]struct{},
+) {
+	children := []string{}
+	for i := range components {
+		if opts.SkipFiles && components[i].Type == onecdx.ComponentTypeFile {
+			skipCDX14Components(components[i:i+1], skipped)
+			continue
+		}
+		n := component14ToNode(&components[i], ids)
+		bom.Nodes = append(bom.Nodes, n)
+		children = append(children, n.Id)
+		addCDX14Components(opts, bom, n.Id, components[i].Components, ids, skipped)
+	}
+
+	if parentID != "" && len(children) > 0 && !opts.SkipRelationships {
+		bom.Edges = append(bom.Edges, &sbom.Edge{
+			Type: sbom.Edge_contains,
+			From: parentID,
+			To:   children,
+		})
+	}
+}
+
+// skipCDX14Components records the refs of a tree of dropped components
+func skipCDX14Components(components []cdx14.Component, skipped map[string]struct{}) {
+	for i := range components {
+		if components[i].Ref != "" {
+			skipped[components[i].Ref] = struct{}{}
+		}
+		skipCDX14Components(components[i].Components, skipped)
+	}
+}
+
+// component14ToNode converts a CycloneDX 1.4 component to a protobom node
+func component14ToNode(c *cdx14.Component, ids *cdxIDGenerator) *sbom.Node {
+	n := &sbom.Node{
+		Id:                 c.Ref,
+		Type:               sbom.Node_PACKAGE,
+		Name:               c.Name,
+		Version:            c.Version,
+		Description:        c.Description,
+		Licenses:           []string{},
+		Hashes:             map[string]string{},
+		Suppliers:          []*sbom.Person{},
+		Originators:        []*sbom.Person{},
+		ExternalReferences: []*sbom.ExternalReference{},
+		Identifiers:        []*sbom.Identifier{},
+	}
+
+	if n.Id == "" {
+		n.Id = ids.next()
+	}
+
+	if c.Type == onecdx.ComponentTypeFile {
+		n.Type = sbom.Node_FILE
+	} else {
+		n.PrimaryPurpose = strings.ToUpper(c.Type)
+	}
+
+	if c.Purl != "" {
+		n.Identifiers = append(n.Identifiers, &sbom.Identifier{
+			Type:  "purl",
+			Value: c.Purl,
+		})
+	}
+
+	for _, h := range c.Hashes {
+		n.Hashes[h.Algorithm] = h.Content
+	}
+
+	for _, l := range c.Licenses {
+		if l.License.ID != "" {
+			n.Licenses = append(n.Licenses, l.License.ID)
+		}
+	}
+
+	for _, er := range c.ExternalReferences {
+		n.ExternalReferences = append(n.ExternalReferences, &sbom.ExternalReference{
+			Url:  er.URL,
+			Type: er.Type,
+		})
+	}
+
+	return n
+}
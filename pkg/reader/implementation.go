@@ -4,9 +4,11 @@
 package reader
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/onesbom/onesbom/pkg/formats"
 	oneparser "github.com/onesbom/onesbom/pkg/reader"
@@ -28,18 +30,75 @@ func (di *defaultParserImplementation) OpenDocumentFile(path string) (*os.File,
 func (di *defaultParserImplementation) DetectFormat(opts *options.Options, r io.ReadSeeker) (formats.Format, error) {
 	sniffer := oneparser.FormatSniffer{}
 	format, err := sniffer.SniffReader(r)
-	if err != nil {
-		return "", fmt.Errorf("detecting format: %w", err)
+	if err == nil {
+		return format, nil
 	}
-	return format, nil
+
+	// The sniffer reads lines, minified JSON is detected from its keys
+	if _, serr := r.Seek(0, io.SeekStart); serr != nil {
+		return "", fmt.Errorf("rewinding document: %w", serr)
+	}
+	defer r.Seek(0, io.SeekStart)
+	if format := sniffJSON(io.LimitReader(r, jsonSniffLimit)); format != "" {
+		return format, nil
+	}
+	return "", fmt.Errorf("detecting format: %w", err)
+}
+
+// jsonSniffLimit is the amount of data read to find the format keys. It
+// also bounds the data buffered from streams while detecting their format.
+const jsonSniffLimit = 1024 * 1024
+
+// sniffJSON returns the format of a JSON document from the keys of its top
+// level object, an empty format if it can't be determined.
+func sniffJSON(r io.Reader) formats.Format {
+	dec := json.NewDecoder(r)
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return ""
+	}
+	values := map[string]string{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		key, _ := t.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return ""
+		}
+		switch key {
+		case "bomFormat", "specVersion", "spdxVersion":
+			values[key], _ = value.(string)
+		default:
+			continue
+		}
+
+		var format string
+		switch {
+		case values["spdxVersion"] != "":
+			format = "text/spdx+json;version=" + strings.TrimPrefix(values["spdxVersion"], "SPDX-")
+		case values["bomFormat"] == "CycloneDX" && values["specVersion"] != "":
+			format = "application/vnd.cyclonedx+json;version=" + values["specVersion"]
+		default:
+			continue
+		}
+		for _, f := range formats.List {
+			if string(f) == format {
+				return f
+			}
+		}
+		return ""
+	}
+	return ""
 }
 
 func (dpi *defaultParserImplementation) GetFormatParser(_ *options.Options, format formats.Format) (FormatParser, error) {
 	switch string(format) {
 	case "text/spdx+json;version=2.3":
 		return &FormatParserSPDX23{}, nil
-	//case "application/vnd.cyclonedx+json;version=1.4":
-	//	return &FormatParserCDX14{}, nil
+	case "application/vnd.cyclonedx+json;version=1.4":
+		return &FormatParserCDX14{}, nil
 	default:
 		return nil, fmt.Errorf("no format parser registered for %s", format)
 	}
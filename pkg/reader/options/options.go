@@ -3,4 +3,53 @@
 
 package options
 
-type Options struct{}
+import (
+	"fmt"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/sirupsen/logrus"
+)
+
+type Options struct {
+	// DiscardElements makes the streaming parsers hand the nodes and edges
+	// to the handlers without keeping them in the returned document, which
+	// then only carries the metadata and root elements.
+	DiscardElements bool
+
+	// Format forces the format of the documents instead of detecting it
+	Format formats.Format
+
+	// Strict makes the problems found in documents errors. Problems are
+	// unknown relationship types, timestamps that can't be parsed and
+	// elements without IDs. When not strict, the problems are handed to
+	// the Warn function and the data is read as well as possible.
+	Strict bool
+
+	// Warn receives the problems found when not in strict mode. The
+	// problems are logged if it is nil. It may be called concurrently
+	// when parsing bundles.
+	Warn func(error)
+
+	// SkipFiles drops the files from the documents, along with the edges
+	// from or to them when the files are read before the relationships.
+	SkipFiles bool
+
+	// SkipRelationships drops the edges from the documents
+	SkipRelationships bool
+}
+
+// Problem reports a problem found in a document. In strict mode it returns
+// the problem as an error, otherwise the problem is a warning and it
+// returns nil.
+func (o *Options) Problem(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if o.Strict {
+		return err
+	}
+	if o.Warn != nil {
+		o.Warn(err)
+	} else {
+		logrus.Warn(err)
+	}
+	return nil
+}
@@ -4,9 +4,13 @@
 package reader
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
+	"github.com/puerco/protobom/pkg/attestation"
 	"github.com/puerco/protobom/pkg/reader/options"
 	"github.com/puerco/protobom/pkg/sbom"
 )
@@ -27,20 +31,41 @@ func New() *Parser {
 
 // Parser reads a file and returns an sbom.Document
 func (p *Parser) ParseFile(path string) (*sbom.Document, error) {
-	f, err := p.impl.OpenDocumentFile(path)
+	f, r, err := p.openDocument(path)
 	if err != nil {
-		return nil, fmt.Errorf("opening SBOM file: %w", err)
+		return nil, err
 	}
 	defer f.Close()
 
-	return p.ParseReader(f)
+	return p.ParseReader(r)
 }
 
-// Parser returns a document from a reader
-func (p *Parser) ParseReader(f io.ReadSeekCloser) (*sbom.Document, error) {
-	format, err := p.impl.DetectFormat(&p.Options, f)
+// openDocument opens an SBOM file. Files with the .gz extension must be
+// gzip compressed, the compression of other files is detected when parsing.
+func (p *Parser) openDocument(path string) (*os.File, io.Reader, error) {
+	f, err := p.impl.OpenDocumentFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening SBOM file: %w", err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, f, nil
+	}
+	zr, err := gzip.NewReader(f)
 	if err != nil {
-		return nil, fmt.Errorf("detecting SBOM format: %w", err)
+		f.Close()
+		return nil, nil, fmt.Errorf("opening gzip file: %w", err)
+	}
+	return f, zr, nil
+}
+
+// Parser returns a document from a reader. The reader does not need to
+// seek, only the data read to detect the format is buffered. SBOMs wrapped
+// in in-toto statements or DSSE envelopes are unwrapped and the statement
+// subjects recorded in the document.
+func (p *Parser) ParseReader(r io.Reader) (*sbom.Document, error) {
+	format, f, statement, err := p.detectFormat(r)
+	if err != nil {
+		return nil, err
 	}
 
 	formatParser, err := p.impl.GetFormatParser(&p.Options, format)
@@ -53,5 +78,9 @@ func (p *Parser) ParseReader(f io.ReadSeekCloser) (*sbom.Document, error) {
 		return nil, fmt.Errorf("parsing %s document: %w", format, err)
 	}
 
+	if statement != nil {
+		doc.Subjects = attestation.SubjectsToProto(statement.Subject)
+	}
+
 	return doc, err
 }
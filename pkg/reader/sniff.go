@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/puerco/protobom/pkg/attestation"
+)
+
+// sniffReader lets the format detection rewind a stream that can't seek.
+// The data read while sniffing is kept in memory and replayed after each
+// rewind, and to the format parser before the rest of the stream. At most
+// limit bytes are recorded, the sniffers see the end of the stream there.
+type sniffReader struct {
+	r       io.Reader
+	buf     []byte
+	pos     int
+	limit   int
+	limited bool
+}
+
+func newSniffReader(r io.Reader, limit int) *sniffReader {
+	return &sniffReader{r: r, limit: limit}
+}
+
+// Read replays the recorded data, then reads from the stream recording it
+func (sr *sniffReader) Read(p []byte) (int, error) {
+	if sr.pos < len(sr.buf) {
+		n := copy(p, sr.buf[sr.pos:])
+		sr.pos += n
+		return n, nil
+	}
+	if len(sr.buf) >= sr.limit {
+		sr.limited = true
+		return 0, io.EOF
+	}
+	if len(p) > sr.limit-len(sr.buf) {
+		p = p[:sr.limit-len(sr.buf)]
+	}
+	n, err := sr.r.Read(p)
+	sr.buf = append(sr.buf, p[:n]...)
+	sr.pos += n
+	return n, err
+}
+
+// Seek only supports rewinding to the start of the stream
+func (sr *sniffReader) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, errors.New("sniffed streams can only be rewound to the start")
+	}
+	sr.pos = 0
+	return 0, nil
+}
+
+// Rewound returns a reader of the whole stream
+func (sr *sniffReader) Rewound() io.Reader {
+	return io.MultiReader(bytes.NewReader(sr.buf), sr.r)
+}
+
+// attestationPeekSize is the amount of data read to detect attestations
+const attestationPeekSize = 4096
+
+// detectFormat decompresses a document, unwraps it if it is an attestation
+// and sniffs its format unless it is forced in the options. It returns the
+// format, a reader of the full document and the statement it was wrapped in,
+// if any.
+func (p *Parser) detectFormat(r io.Reader) (formats.Format, io.Reader, *attestation.Statement, error) {
+	r, _, err := decompress(r)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("decompressing document: %w", err)
+	}
+
+	r, statement, err := unwrapAttestation(r)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("unwrapping attestation: %w", err)
+	}
+
+	// A forced format is not checked against the document
+	if p.Options.Format != "" {
+		return p.Options.Format, r, statement, nil
+	}
+
+	sr := newSniffReader(r, jsonSniffLimit)
+	format, err := p.impl.DetectFormat(&p.Options, sr)
+	if err != nil {
+		if sr.limited {
+			return "", nil, nil, fmt.Errorf(
+				"SBOM format not found in the first %d bytes, force it in the options: %w", jsonSniffLimit, err,
+			)
+		}
+		return "", nil, nil, fmt.Errorf("detecting SBOM format: %w", err)
+	}
+	return format, sr.Rewound(), statement, nil
+}
+
+// unwrapAttestation returns a reader of the SBOM predicate when the stream
+// is an in-toto statement or a DSSE envelope. Attestations are decoded in
+// memory, other documents are returned as they are.
+func unwrapAttestation(r io.Reader) (io.Reader, *attestation.Statement, error) {
+	br := bufio.NewReaderSize(r, attestationPeekSize)
+	header, err := br.Peek(attestationPeekSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, nil, fmt.Errorf("reading document header: %w", err)
+	}
+	if attestation.DetectWrapper(header) == attestation.WrapperNone {
+		return br, nil, nil
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading attestation: %w", err)
+	}
+	statement, err := attestation.Unwrap(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	predicate, err := statement.SBOM()
+	if err != nil {
+		return nil, nil, err
+	}
+	return bytes.NewReader(predicate), statement, nil
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/puerco/protobom/pkg/attestation"
+	"github.com/puerco/protobom/pkg/reader/options"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// errStopped is returned by the channel handlers when the consumer cancels
+var errStopped = errors.New("parsing stopped")
+
+// StreamHandlers receive the elements of a document as they are decoded.
+// Any of the functions may be nil. An error returned by a handler stops
+// the parsing.
+type StreamHandlers struct {
+	// Metadata is called once before the first node or edge with the
+	// metadata decoded up to that point. Fields appearing after the
+	// elements in the file are only set in the returned document.
+	Metadata func(*sbom.Metadata) error
+
+	// Node is called for each package or file
+	Node func(*sbom.Node) error
+
+	// Edge is called for each relationship
+	Edge func(*sbom.Edge) error
+}
+
+// StreamParser is implemented by the format parsers that decode documents
+// incrementally instead of loading them in memory.
+type StreamParser interface {
+	ParseStream(*options.Options, io.Reader, *StreamHandlers) (*sbom.Document, error)
+}
+
+// Element is a node or an edge handed out by ParseElements
+type Element struct {
+	Node *sbom.Node
+	Edge *sbom.Edge
+}
+
+// ParseStreamFile parses a file, handing its elements to the handlers
+func (p *Parser) ParseStreamFile(path string, handlers *StreamHandlers) (*sbom.Document, error) {
+	f, r, err := p.openDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return p.ParseStream(r, handlers)
+}
+
+// ParseStream parses a document, handing its nodes and edges to the
+// handlers as they are decoded. The returned document has all the elements
+// unless the DiscardElements option is set. Formats without a streaming
+// parser are decoded in memory and their elements replayed to the handlers.
+func (p *Parser) ParseStream(r io.Reader, handlers *StreamHandlers) (*sbom.Document, error) {
+	format, f, statement, err := p.detectFormat(r)
+	if err != nil {
+		return nil, err
+	}
+
+	formatParser, err := p.impl.GetFormatParser(&p.Options, format)
+	if err != nil {
+		return nil, fmt.Errorf("getting format parser: %w", err)
+	}
+
+	var doc *sbom.Document
+	if sp, ok := formatParser.(StreamParser); ok {
+		doc, err = sp.ParseStream(&p.Options, f, handlers)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s document: %w", format, err)
+		}
+	} else {
+		doc, err = formatParser.Parse(&p.Options, f)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s document: %w", format, err)
+		}
+		if err := replayElements(&p.Options, doc, handlers); err != nil {
+			return nil, err
+		}
+	}
+
+	if statement != nil {
+		doc.Subjects = attestation.SubjectsToProto(statement.Subject)
+	}
+	return doc, nil
+}
+
+// ParseElements parses a document in the background, sending its nodes and
+// edges through the returned channel. The elements are not kept in memory.
+// Both channels are closed when parsing finishes, the error channel
+// receives the parsing error if there is one. Cancelling the context stops
+// the parsing.
+func (p *Parser) ParseElements(ctx context.Context, f io.Reader) (<-chan *Element, <-chan error) {
+	elements := make(chan *Element)
+	errc := make(chan error, 1)
+
+	send := func(e *Element) error {
+		select {
+		case elements <- e:
+			return nil
+		case <-ctx.Done():
+			return errStopped
+		}
+	}
+
+	go func() {
+		defer close(errc)
+		defer close(elements)
+
+		parser := &Parser{impl: p.impl, Options: p.Options}
+		parser.Options.DiscardElements = true
+		_, err := parser.ParseStream(f, &StreamHandlers{
+			Node: func(n *sbom.Node) error { return send(&Element{Node: n}) },
+			Edge: func(e *sbom.Edge) error { return send(&Element{Edge: e}) },
+		})
+		if errors.Is(err, errStopped) {
+			err = ctx.Err()
+		}
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return elements, errc
+}
+
+// replayElements hands the elements of a parsed document to the handlers
+func replayElements(opts *options.Options, doc *sbom.Document, handlers *StreamHandlers) error {
+	if handlers == nil {
+		return nil
+	}
+	if handlers.Metadata != nil {
+		if err := handlers.Metadata(doc.Metadata); err != nil {
+			return err
+		}
+	}
+	for _, n := range doc.Nodes {
+		if handlers.Node == nil {
+			break
+		}
+		if err := handlers.Node(n); err != nil {
+			return err
+		}
+	}
+	for _, e := range doc.Edges {
+		if handlers.Edge == nil {
+			break
+		}
+		if err := handlers.Edge(e); err != nil {
+			return err
+		}
+	}
+	if opts.DiscardElements {
+		doc.Nodes = []*sbom.Node{}
+		doc.Edges = []*sbom.Edge{}
+	}
+	return nil
+}
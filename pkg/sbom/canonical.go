@@ -0,0 +1,118 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Canonicalize sorts the nodes, edges and the repeated fields of the document
+// into a deterministic order. Two documents holding the same data will have
+// the same canonical ordering regardless of the order in which the data was
+// added. The root elements are not reordered as their order is meaningful.
+func (x *Document) Canonicalize() {
+	if x == nil {
+		return
+	}
+
+	for _, n := range x.Nodes {
+		n.Canonicalize()
+	}
+
+	sort.SliceStable(x.Nodes, func(i, j int) bool {
+		return x.Nodes[i].Id < x.Nodes[j].Id
+	})
+
+	for _, e := range x.Edges {
+		e.Canonicalize()
+	}
+
+	sort.SliceStable(x.Edges, func(i, j int) bool {
+		return compareEdges(x.Edges[i], x.Edges[j]) < 0
+	})
+
+	for _, v := range x.Vulnerabilities {
+		v.Canonicalize()
+	}
+
+	sort.SliceStable(x.Vulnerabilities, func(i, j int) bool {
+		return x.Vulnerabilities[i].Id < x.Vulnerabilities[j].Id
+	})
+}
+
+// MarshalCanonical returns the protobuf serialization of a canonicalized copy
+// of the document. Map fields are marshaled in sorted key order so the
+// resulting bytes are stable between runs.
+func (x *Document) MarshalCanonical() ([]byte, error) {
+	doc, ok := proto.Clone(x).(*Document)
+	if !ok || doc == nil {
+		doc = &Document{}
+	}
+	doc.Canonicalize()
+	return proto.MarshalOptions{Deterministic: true}.Marshal(doc)
+}
+
+// Canonicalize sorts the repeated fields of the node where the order carries
+// no meaning.
+func (x *Node) Canonicalize() {
+	if x == nil {
+		return
+	}
+	sort.Strings(x.Licenses)
+	sort.Strings(x.FileTypes)
+	sort.SliceStable(x.Identifiers, func(i, j int) bool {
+		if x.Identifiers[i].Type != x.Identifiers[j].Type {
+			return x.Identifiers[i].Type < x.Identifiers[j].Type
+		}
+		return x.Identifiers[i].Value < x.Identifiers[j].Value
+	})
+	sort.SliceStable(x.ExternalReferences, func(i, j int) bool {
+		if x.ExternalReferences[i].Type != x.ExternalReferences[j].Type {
+			return x.ExternalReferences[i].Type < x.ExternalReferences[j].Type
+		}
+		return x.ExternalReferences[i].Url < x.ExternalReferences[j].Url
+	})
+	sort.SliceStable(x.Properties, func(i, j int) bool {
+		if x.Properties[i].Name != x.Properties[j].Name {
+			return x.Properties[i].Name < x.Properties[j].Name
+		}
+		return x.Properties[i].Value < x.Properties[j].Value
+	})
+}
+
+// Canonicalize sorts the destination IDs of the edge
+func (x *Edge) Canonicalize() {
+	if x == nil {
+		return
+	}
+	sort.Strings(x.To)
+}
+
+// Canonicalize sorts the aliases of the vulnerability and the nodes it affects
+func (x *Vulnerability) Canonicalize() {
+	if x == nil {
+		return
+	}
+	sort.Strings(x.Aliases)
+	sort.SliceStable(x.Affects, func(i, j int) bool {
+		return x.Affects[i].NodeId < x.Affects[j].NodeId
+	})
+}
+
+// compareEdges orders two edges by their origin, type and destinations
+func compareEdges(a, b *Edge) int {
+	switch {
+	case a.From != b.From:
+		return strings.Compare(a.From, b.From)
+	case a.Type != b.Type:
+		return int(a.Type) - int(b.Type)
+	}
+
+	for i := 0; i < len(a.To) && i < len(b.To); i++ {
+		if a.To[i] != b.To[i] {
+			return strings.Compare(a.To[i], b.To[i])
+		}
+	}
+	return len(a.To) - len(b.To)
+}
@@ -0,0 +1,88 @@
+package sbom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ElementCoverage records how many of the nodes in a document have a
+// required data element.
+type ElementCoverage struct {
+	ID       string `json:"id"`
+	Element  string `json:"element"`
+	Required bool   `json:"required"`
+	Covered  int    `json:"covered"`
+	Total    int    `json:"total"`
+}
+
+// Percentage returns the coverage of the element from 0 to 100
+func (ec *ElementCoverage) Percentage() float64 {
+	if ec.Total == 0 {
+		return 100
+	}
+	return float64(ec.Covered) * 100 / float64(ec.Total)
+}
+
+// ComplianceReport captures the result of checking a document against a
+// set of required data elements.
+type ComplianceReport struct {
+	Profile      string              `json:"profile"`
+	ProfileID    string              `json:"profileId"`
+	Coverage     []*ElementCoverage  `json:"coverage"`
+	DocumentGaps []string            `json:"documentGaps"`
+	NodeGaps     map[string][]string `json:"nodeGaps"`
+}
+
+// Compliant returns true when all required elements are covered in all the
+// nodes they apply to.
+func (r *ComplianceReport) Compliant() bool {
+	for _, cov := range r.Coverage {
+		if cov.Required && cov.Covered < cov.Total {
+			return false
+		}
+	}
+	return true
+}
+
+// finding builds a validation finding for a missing element
+func (r *ComplianceReport) finding(element, subject, nodeID string) *Finding {
+	f := &Finding{
+		RuleID:   fmt.Sprintf("%s:%s", r.ProfileID, strings.ReplaceAll(element, " ", "-")),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s is missing %s", subject, element),
+		NodeID:   nodeID,
+	}
+	for _, cov := range r.Coverage {
+		if cov.Element != element {
+			continue
+		}
+		if cov.ID != "" {
+			f.RuleID = fmt.Sprintf("%s:%s", r.ProfileID, cov.ID)
+		}
+		if !cov.Required {
+			f.Severity = SeverityWarning
+		}
+		break
+	}
+	return f
+}
+
+// Findings returns the gaps in the report as validation findings
+func (r *ComplianceReport) Findings() Findings {
+	findings := Findings{}
+	for _, gap := range r.DocumentGaps {
+		findings = append(findings, r.finding(gap, "document", ""))
+	}
+	ids := make([]string, 0, len(r.NodeGaps))
+	for id := range r.NodeGaps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, gap := range r.NodeGaps[id] {
+			findings = append(findings, r.finding(gap, "node", id))
+		}
+	}
+	return findings
+}
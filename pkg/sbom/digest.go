@@ -0,0 +1,276 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DigestPrefix is prepended to all digests and fingerprints to note the
+// algorithm used to compute them.
+const DigestPrefix = "sha256:"
+
+// DigestOptions control which parts of the document are considered when
+// computing its digest.
+type DigestOptions struct {
+	// IncludeDate adds the creation date of the document to the digest. It is
+	// off by default as the date changes every time an SBOM is regenerated.
+	IncludeDate bool
+
+	// IncludeMetadata adds the document name, tools and authors to the digest.
+	IncludeMetadata bool
+}
+
+// nodeDigestData captures the fields of a node which survive the translation
+// between all supported formats. Its JSON serialization is what gets hashed
+// to compute the node fingerprint.
+type nodeDigestData struct {
+	Type        string   `json:"type"`
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Hashes      []string `json:"hashes"`
+	License     string   `json:"license"`
+	Identifiers []string `json:"identifiers"`
+}
+
+// documentDigestData is the normalized representation of a document
+type documentDigestData struct {
+	Date     string   `json:"date,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Tools    []string `json:"tools,omitempty"`
+	Authors  []string `json:"authors,omitempty"`
+	Roots    []string `json:"roots"`
+	Nodes    []string `json:"nodes"`
+	Edges    []string `json:"edges"`
+	Unlinked int      `json:"unlinked"`
+}
+
+// NormalizeHashAlgorithm returns the name of a hash algorithm in a uniform
+// way. SPDX and CycloneDX spell the algorithms differently (SHA256 vs SHA-256),
+// the normalized form is uppercase without separators.
+func NormalizeHashAlgorithm(algo string) string {
+	algo = strings.ToUpper(strings.TrimSpace(algo))
+	algo = strings.ReplaceAll(algo, "-", "")
+	algo = strings.ReplaceAll(algo, "_", "")
+	return algo
+}
+
+// portableIdentifier returns true for the identifier types that survive
+// the translation between formats
+func portableIdentifier(idType string) bool {
+	return strings.EqualFold(idType, "purl") || strings.EqualFold(idType, "cpe23Type")
+}
+
+// Fingerprint returns a digest of the node data that is independent from the
+// node ID and the SBOM format the node was read from. Two nodes describing
+// the same component in different formats produce the same fingerprint.
+func (x *Node) Fingerprint() string {
+	data := nodeDigestData{
+		Type:        x.GetType().String(),
+		Name:        strings.TrimSpace(x.GetName()),
+		Version:     strings.TrimSpace(x.GetVersion()),
+		Hashes:      []string{},
+		License:     licenseExpression(x.GetLicenses()),
+		Identifiers: []string{},
+	}
+
+	for algo, value := range x.GetHashes() {
+		data.Hashes = append(data.Hashes, fmt.Sprintf(
+			"%s:%s", NormalizeHashAlgorithm(algo), strings.ToLower(strings.TrimSpace(value)),
+		))
+	}
+
+	// Identifiers can be stored as identifiers (SPDX) or external
+	// references (CycloneDX purls), so we read both. Other identifier types
+	// are format specific and don't count.
+	idents := map[string]struct{}{}
+	for _, i := range x.GetIdentifiers() {
+		if portableIdentifier(i.Type) {
+			idents[fmt.Sprintf("%s:%s", strings.ToLower(i.Type), strings.TrimSpace(i.Value))] = struct{}{}
+		}
+	}
+	for _, er := range x.GetExternalReferences() {
+		if portableIdentifier(er.Type) {
+			idents[fmt.Sprintf("%s:%s", strings.ToLower(er.Type), strings.TrimSpace(er.Url))] = struct{}{}
+		}
+	}
+	for i := range idents {
+		data.Identifiers = append(data.Identifiers, i)
+	}
+
+	sort.Strings(data.Hashes)
+	sort.Strings(data.Identifiers)
+
+	// Marshaling a struct of strings can't fail
+	b, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	return hashBytes(b)
+}
+
+// licenseExpression returns the licenses of a node as a single normalized
+// expression. SPDX writes the licenses of a package joined with AND while
+// other formats list them, so the terms of the conjunction are sorted and
+// deduplicated. NOASSERTION and NONE carry no license data and are dropped.
+func licenseExpression(licenses []string) string {
+	terms := map[string]struct{}{}
+	for _, l := range licenses {
+		for _, term := range splitConjunction(l) {
+			switch strings.ToUpper(term) {
+			case "", "NOASSERTION", "NONE":
+				continue
+			}
+			terms[term] = struct{}{}
+		}
+	}
+	ret := []string{}
+	for term := range terms {
+		ret = append(ret, term)
+	}
+	sort.Strings(ret)
+	for i, term := range ret {
+		// Compound terms are grouped as AND binds tighter than OR
+		if strings.Contains(term, " ") {
+			ret[i] = "(" + term + ")"
+		}
+	}
+	return strings.Join(ret, " AND ")
+}
+
+// splitConjunction splits a license expression at its top level ANDs. The
+// parentheses enclosing a whole term are removed.
+func splitConjunction(expression string) []string {
+	fields := strings.Fields(expression)
+	terms := []string{}
+	current := []string{}
+	depth := 0
+	for _, f := range fields {
+		if depth == 0 && strings.EqualFold(f, "AND") {
+			terms = append(terms, unwrapLicenseTerm(strings.Join(current, " ")))
+			current = []string{}
+			continue
+		}
+		depth += strings.Count(f, "(") - strings.Count(f, ")")
+		current = append(current, f)
+	}
+	return append(terms, unwrapLicenseTerm(strings.Join(current, " ")))
+}
+
+// unwrapLicenseTerm removes the parentheses around a license term when they
+// enclose all of it.
+func unwrapLicenseTerm(term string) string {
+	for strings.HasPrefix(term, "(") && strings.HasSuffix(term, ")") {
+		depth := 0
+		for i, c := range term {
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			// The first parenthesis closes before the end
+			if depth == 0 && i < len(term)-1 {
+				return term
+			}
+		}
+		term = strings.TrimSpace(term[1 : len(term)-1])
+	}
+	return term
+}
+
+// Digest computes a hash of the document contents which is independent of
+// the SBOM format it was read from. Nodes and edges are normalized and sorted
+// and element IDs are replaced by node fingerprints before hashing, so the
+// digest is stable across serializations.
+func (x *Document) Digest(opts *DigestOptions) (string, error) {
+	if x == nil {
+		return "", fmt.Errorf("unable to compute digest of nil document")
+	}
+
+	if opts == nil {
+		opts = &DigestOptions{}
+	}
+
+	data := documentDigestData{
+		Roots: []string{},
+		Nodes: []string{},
+		Edges: []string{},
+	}
+
+	fingerprints := x.Fingerprints()
+	for _, fp := range fingerprints {
+		data.Nodes = append(data.Nodes, fp)
+	}
+
+	for _, id := range x.RootElements {
+		if fp, ok := fingerprints[id]; ok {
+			data.Roots = append(data.Roots, fp)
+		}
+	}
+
+	// Edges are keyed by the fingerprints of their ends and deduplicated, as
+	// some formats express the same relationship more than once.
+	edges := map[string]struct{}{}
+	for _, e := range x.Edges {
+		from, ok := fingerprints[e.From]
+		if !ok {
+			data.Unlinked++
+			continue
+		}
+		for _, to := range e.To {
+			toFp, ok := fingerprints[to]
+			if !ok {
+				data.Unlinked++
+				continue
+			}
+			edges[fmt.Sprintf("%s %s %s", from, e.Type.String(), toFp)] = struct{}{}
+		}
+	}
+	for e := range edges {
+		data.Edges = append(data.Edges, e)
+	}
+
+	if opts.IncludeDate && x.GetMetadata().GetDate() != nil {
+		data.Date = x.Metadata.Date.AsTime().UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	if opts.IncludeMetadata && x.Metadata != nil {
+		data.Name = x.Metadata.Name
+		for _, t := range x.Metadata.Tools {
+			data.Tools = append(data.Tools, fmt.Sprintf("%s %s %s", t.Vendor, t.Name, t.Version))
+		}
+		for _, a := range x.Metadata.Authors {
+			data.Authors = append(data.Authors, fmt.Sprintf("%s <%s>", a.Name, a.Email))
+		}
+		sort.Strings(data.Tools)
+		sort.Strings(data.Authors)
+	}
+
+	sort.Strings(data.Roots)
+	sort.Strings(data.Nodes)
+	sort.Strings(data.Edges)
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("marshaling digest data: %w", err)
+	}
+	return hashBytes(b), nil
+}
+
+// Fingerprints returns a map of the document's node IDs to their fingerprints
+func (x *Document) Fingerprints() map[string]string {
+	ret := map[string]string{}
+	for _, n := range x.GetNodes() {
+		ret[n.Id] = n.Fingerprint()
+	}
+	return ret
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return DigestPrefix + hex.EncodeToString(sum[:])
+}
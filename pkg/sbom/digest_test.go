@@ -0,0 +1,90 @@
+package sbom_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/puerco/protobom/pkg/reader"
+	"github.com/puerco/protobom/pkg/sbom"
+	"github.com/puerco/protobom/pkg/writer"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (bufferCloser) Close() error { return nil }
+
+func sortedFingerprints(doc *sbom.Document) []string {
+	ret := []string{}
+	for _, fp := range doc.Fingerprints() {
+		ret = append(ret, fp)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// TestDigestRoundTrip checks that the node fingerprints survive writing the
+// examples to each format and reading them back, and that the document
+// digest does too when no data is lost in the conversion.
+func TestDigestRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "..", "examples", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no examples found")
+	}
+
+	for _, path := range paths {
+		doc, err := reader.New().ParseFile(path)
+		if err != nil {
+			t.Fatalf("parsing %s: %v", path, err)
+		}
+		digest, err := doc.Digest(nil)
+		if err != nil {
+			t.Fatalf("computing digest of %s: %v", path, err)
+		}
+		fingerprints := sortedFingerprints(doc)
+
+		for _, format := range []formats.Format{formats.SPDX23JSON, formats.CDX14JSON} {
+			w := writer.New()
+			w.Options.Format = format
+			buf := &bufferCloser{}
+			deg, err := w.WriteStreamReport(doc, buf)
+			if err != nil {
+				t.Fatalf("writing %s as %s: %v", path, format, err)
+			}
+			doc2, err := reader.New().ParseReader(&buf.Buffer)
+			if err != nil {
+				t.Fatalf("reading %s written as %s: %v", path, format, err)
+			}
+
+			fingerprints2 := sortedFingerprints(doc2)
+			if len(fingerprints) != len(fingerprints2) {
+				t.Errorf("%s as %s: got %d fingerprints, expected %d", path, format, len(fingerprints2), len(fingerprints))
+				continue
+			}
+			for i := range fingerprints {
+				if fingerprints[i] != fingerprints2[i] {
+					t.Errorf("%s as %s: node fingerprints changed", path, format)
+					break
+				}
+			}
+
+			if deg.Total() > 0 {
+				continue
+			}
+			digest2, err := doc2.Digest(nil)
+			if err != nil {
+				t.Fatalf("computing digest of %s written as %s: %v", path, format, err)
+			}
+			if digest != digest2 {
+				t.Errorf("%s as %s: digest changed from %s to %s", path, format, digest, digest2)
+			}
+		}
+	}
+}
@@ -1,92 +1,81 @@
 package sbom
 
+import "strings"
+
+// Purl returns the package URL of the node. Parsers store purls either as an
+// identifier or as an external reference, this function looks in both.
+func (x *Node) Purl() string {
+	for _, i := range x.GetIdentifiers() {
+		if strings.EqualFold(i.Type, "purl") && i.Value != "" {
+			return i.Value
+		}
+	}
+	for _, er := range x.GetExternalReferences() {
+		if strings.EqualFold(er.Type, "purl") && er.Url != "" {
+			return er.Url
+		}
+	}
+	return ""
+}
+
+// spdxRelationshipTypes maps the SPDX relationship types to edge types
+var spdxRelationshipTypes = map[string]Edge_Type{
+	"AMENDS":                      Edge_amends,
+	"ANCESTOR_OF":                 Edge_ancestor,
+	"BUILD_DEPENDENCY_OF":         Edge_buildDependency,
+	"BUILD_TOOL_OF":               Edge_buildTool,
+	"CONTAINS":                    Edge_contains,
+	"COPY_OF":                     Edge_copy,
+	"DATA_FILE_OF":                Edge_dataFile,
+	"DEPENDENCY_MANIFEST_OF":      Edge_dependencyManifest,
+	"DEPENDS_ON":                  Edge_dependsOn,
+	"DESCENDANT_OF":               Edge_descendant,
+	"DESCRIBES":                   Edge_describes,
+	"DEV_DEPENDENCY_OF":           Edge_devDependency,
+	"DEV_TOOL_OF":                 Edge_devTool,
+	"DISTRIBUTION_ARTIFACT":       Edge_distributionArtifact,
+	"DOCUMENTATION_OF":            Edge_documentation,
+	"DYNAMIC_LINK":                Edge_dynamicLink,
+	"EXAMPLE_OF":                  Edge_example,
+	"EXPANDED_FROM_ARCHIVE":       Edge_expandedFromArchive,
+	"FILE_ADDED":                  Edge_fileAdded,
+	"FILE_DELETED":                Edge_fileDeleted,
+	"FILE_MODIFIED":               Edge_fileModified,
+	"GENERATES":                   Edge_generates,
+	"METAFILE_OF":                 Edge_metafile,
+	"OPTIONAL_COMPONENT_OF":       Edge_optionalComponent,
+	"OPTIONAL_DEPENDENCY_OF":      Edge_optionalDependency,
+	"OTHER":                       Edge_other,
+	"PACKAGE_OF":                  Edge_packages,
+	"PATCH_FOR":                   Edge_patch,
+	"HAS_PREREQUISITE":            Edge_prerequisite,
+	"PROVIDED_DEPENDENCY_OF":      Edge_providedDependency,
+	"REQUIREMENT_DESCRIPTION_FOR": Edge_requirementFor,
+	"RUNTIME_DEPENDENCY_OF":       Edge_runtimeDependency,
+	"SPECIFICATION_FOR":           Edge_specificationFor,
+	"STATIC_LINK":                 Edge_staticLink,
+	"TEST_OF":                     Edge_test,
+	"TEST_CASE_OF":                Edge_testCase,
+	"TEST_DEPENDENCY_OF":          Edge_testDependency,
+	"TEST_TOOL_OF":                Edge_testTool,
+	"VARIANT_OF":                  Edge_variant,
+}
+
+// EdgeTypeFromSPDX returns the edge type of an SPDX relationship type
 func EdgeTypeFromSPDX(spdxName string) Edge_Type {
-	switch spdxName {
-	case "AMENDS":
-		return Edge_amends
-	case "ANCESTOR_OF":
-		return Edge_ancestor
-	case "BUILD_DEPENDENCY_OF":
-		return Edge_buildDependency
-	case "BUILD_TOOL_OF":
-		return Edge_buildTool
-		// case "CONTAINED_BY":
-	case "CONTAINS":
-		return Edge_contains
-	case "COPY_OF":
-		return Edge_copy
-	case "DATA_FILE_OF":
-		return Edge_dataFile
-	case "DEPENDENCY_MANIFEST_OF":
-		return Edge_dependencyManifest
-		// case "DEPENDENCY_OF":
-	case "DEPENDS_ON":
-		return Edge_dependsOn
-	case "DESCENDANT_OF":
-		return Edge_descendant
-		// case "DESCRIBED_BY":
-	case "DESCRIBES":
-		return Edge_describes
-	case "DEV_DEPENDENCY_OF":
-		return Edge_devDependency
-	case "DEV_TOOL_OF":
-		return Edge_devTool
-	case "DISTRIBUTION_ARTIFACT":
-		return Edge_distributionArtifact
-	case "DOCUMENTATION_OF":
-		return Edge_documentation
-	case "DYNAMIC_LINK":
-		return Edge_dynamicLink
-	case "EXAMPLE_OF":
-		return Edge_example
-	case "EXPANDED_FROM_ARCHIVE":
-		return Edge_expandedFromArchive
-	case "FILE_ADDED":
-		return Edge_fileAdded
-	case "FILE_DELETED":
-		return Edge_fileDeleted
-	case "FILE_MODIFIED":
-		return Edge_fileModified
-		// case "GENERATED_FROM":
-	case "GENERATES":
-		return Edge_generates
-	case "METAFILE_OF":
-		return Edge_metafile
-	case "OPTIONAL_COMPONENT_OF":
-		return Edge_optionalComponent
-	case "OPTIONAL_DEPENDENCY_OF":
-		return Edge_optionalDependency
-	case "OTHER":
-		return Edge_other
-	case "PACKAGE_OF":
-		return Edge_packages
-	// case "PATCH_APPLIED":
-	case "PATCH_FOR":
-		return Edge_patch
-	// case "PREREQUISITE_FOR":
-	case "HAS_PREREQUISITE":
-		return Edge_prerequisite
-	case "PROVIDED_DEPENDENCY_OF":
-		return Edge_providedDependency
-	case "REQUIREMENT_DESCRIPTION_FOR":
-		return Edge_requirementFor
-	case "RUNTIME_DEPENDENCY_OF":
-		return Edge_runtimeDependency
-	case "SPECIFICATION_FOR":
-		return Edge_specificationFor
-	case "STATIC_LINK":
-		return Edge_staticLink
-	case "TEST_OF":
-		return Edge_test
-	case "TEST_CASE_OF":
-		return Edge_testCase
-	case "TEST_DEPENDENCY_OF":
-		return Edge_testDependency
-	case "TEST_TOOL_OF":
-		return Edge_testTool
-	case "VARIANT_OF":
-		return Edge_variant
-	default:
-		return Edge_UNKNOWN
+	if t, ok := spdxRelationshipTypes[spdxName]; ok {
+		return t
+	}
+	return Edge_UNKNOWN
+}
+
+// EdgeTypeToSPDX returns the SPDX relationship type of an edge type. Types
+// without an SPDX equivalent are returned as OTHER.
+func EdgeTypeToSPDX(t Edge_Type) string {
+	for name, et := range spdxRelationshipTypes {
+		if et == t {
+			return name
+		}
 	}
+	return "OTHER"
 }
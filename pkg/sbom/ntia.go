@@ -0,0 +1,42 @@
+package sbom
+
+import (
+	"strings"
+)
+
+// NTIA minimum elements as defined in "The Minimum Elements For a Software
+// Bill of Materials (SBOM)", published by the NTIA in July 2021.
+const (
+	NTIASupplierName           = "supplier name"
+	NTIAComponentName          = "component name"
+	NTIAComponentVersion       = "version"
+	NTIAUniqueIdentifiers      = "unique identifiers"
+	NTIADependencyRelationship = "dependency relationship"
+	NTIAAuthor                 = "author of sbom data"
+	NTIATimestamp              = "timestamp"
+)
+
+// CheckNTIA verifies that the document and all its package nodes contain the
+// NTIA minimum data elements.
+func CheckNTIA(doc *Document) *ComplianceReport {
+	return NTIAProfile.Check(doc)
+}
+
+// hasUniqueIdentifier returns true if the node has a software identifier
+// such as a purl, CPE or SWID tag.
+func hasUniqueIdentifier(n *Node) bool {
+	for _, i := range n.Identifiers {
+		if strings.TrimSpace(i.Value) != "" {
+			return true
+		}
+	}
+	for _, er := range n.ExternalReferences {
+		switch strings.ToLower(er.Type) {
+		case "purl", "cpe22type", "cpe23type", "swid":
+			if strings.TrimSpace(er.Url) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
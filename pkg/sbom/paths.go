@@ -0,0 +1,69 @@
+package sbom
+
+import "sort"
+
+// RootPaths records the paths from the document roots to its nodes
+// following the contains and dependsOn edges.
+type RootPaths struct {
+	parents map[string]string
+	roots   map[string]struct{}
+}
+
+// RootPaths indexes the shortest paths from the root elements to each node
+func (x *Document) RootPaths() *RootPaths {
+	children := map[string][]string{}
+	for _, e := range x.Edges {
+		if e.Type != Edge_contains && e.Type != Edge_dependsOn {
+			continue
+		}
+		children[e.From] = append(children[e.From], e.To...)
+	}
+
+	pf := &RootPaths{
+		parents: map[string]string{},
+		roots:   map[string]struct{}{},
+	}
+
+	// Breadth first search to record the shortest path to each node
+	queue := []string{}
+	for _, id := range x.RootElements {
+		pf.roots[id] = struct{}{}
+		queue = append(queue, id)
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		targets := children[id]
+		sort.Strings(targets)
+		for _, to := range targets {
+			if _, ok := pf.roots[to]; ok {
+				continue
+			}
+			if _, ok := pf.parents[to]; ok {
+				continue
+			}
+			pf.parents[to] = id
+			queue = append(queue, to)
+		}
+	}
+	return pf
+}
+
+// PathTo returns the node IDs from a root to the specified node. Nodes not
+// reachable from the roots return a path with only themselves.
+func (pf *RootPaths) PathTo(id string) []string {
+	path := []string{id}
+	seen := map[string]struct{}{id: {}}
+	for {
+		parent, ok := pf.parents[path[0]]
+		if !ok {
+			break
+		}
+		if _, ok := seen[parent]; ok {
+			break
+		}
+		seen[parent] = struct{}{}
+		path = append([]string{parent}, path...)
+	}
+	return path
+}
@@ -0,0 +1,416 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// ScopeDocument marks requirements checked once on the document
+	ScopeDocument = "document"
+	// ScopeNode marks requirements checked on each node
+	ScopeNode = "node"
+)
+
+// Requirement is a data element that a compliance profile expects to find
+// in a document. Requirements are pure data: they reference one of the
+// registered field checks by name so profiles can be loaded from JSON.
+type Requirement struct {
+	// ID is the identifier of the requirement in the profile specification
+	ID string `json:"id"`
+
+	// Element is a human readable name of the required data element
+	Element string `json:"element"`
+
+	// Scope is either "document" or "node"
+	Scope string `json:"scope"`
+
+	// Field is the name of the check used to verify the requirement. It
+	// must be a key in DocumentFieldChecks or NodeFieldChecks.
+	Field string `json:"field"`
+
+	// Values are optional arguments to the field check, for example the
+	// hash algorithms accepted by the "hashes" check.
+	Values []string `json:"values,omitempty"`
+
+	// NodeTypes limits the nodes checked to those of the listed types
+	// (PACKAGE, FILE). When empty, all nodes are checked.
+	NodeTypes []string `json:"nodeTypes,omitempty"`
+
+	// Optional requirements are reported but do not break compliance
+	Optional bool `json:"optional,omitempty"`
+}
+
+// Profile is a named set of requirements documents must meet
+type Profile struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description,omitempty"`
+	Requirements []*Requirement `json:"requirements"`
+}
+
+// CheckContext gives node field checks access to the document being checked
+// and caches data shared between the checks of all its nodes.
+type CheckContext struct {
+	Document *Document
+	related  map[string]struct{}
+}
+
+// IsRelated returns true if the node with the specified ID is at either end
+// of an edge in the document.
+func (ctx *CheckContext) IsRelated(id string) bool {
+	if ctx.related == nil {
+		ctx.related = map[string]struct{}{}
+		for _, e := range ctx.Document.GetEdges() {
+			ctx.related[e.From] = struct{}{}
+			for _, to := range e.To {
+				ctx.related[to] = struct{}{}
+			}
+		}
+	}
+	_, ok := ctx.related[id]
+	return ok
+}
+
+// NodeFieldCheck returns true if a node has the data required by a field
+type NodeFieldCheck func(ctx *CheckContext, n *Node, values []string) bool
+
+// DocumentFieldCheck returns true if a document has the required data
+type DocumentFieldCheck func(doc *Document, values []string) bool
+
+// DocumentFieldChecks are the checks available to document requirements
+var DocumentFieldChecks = map[string]DocumentFieldCheck{
+	"authors": func(doc *Document, _ []string) bool {
+		for _, a := range doc.GetMetadata().GetAuthors() {
+			if strings.TrimSpace(a.Name) != "" {
+				return true
+			}
+		}
+		return false
+	},
+	"authors-contact": func(doc *Document, _ []string) bool {
+		for _, a := range doc.GetMetadata().GetAuthors() {
+			if strings.TrimSpace(a.Email) != "" || strings.TrimSpace(a.Url) != "" {
+				return true
+			}
+		}
+		return false
+	},
+	"timestamp": func(doc *Document, _ []string) bool {
+		return isTimestampSet(doc.GetMetadata().GetDate())
+	},
+	"tools": func(doc *Document, _ []string) bool {
+		return len(doc.GetMetadata().GetTools()) > 0
+	},
+	"id": func(doc *Document, _ []string) bool {
+		return strings.TrimSpace(doc.GetMetadata().GetId()) != ""
+	},
+	"root-elements": func(doc *Document, _ []string) bool {
+		for _, id := range doc.GetRootElements() {
+			if doc.GetNodeByID(id) != nil {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// NodeFieldChecks are the checks available to node requirements
+var NodeFieldChecks = map[string]NodeFieldCheck{
+	"name": func(_ *CheckContext, n *Node, _ []string) bool {
+		return strings.TrimSpace(n.Name) != ""
+	},
+	"version": func(_ *CheckContext, n *Node, _ []string) bool {
+		return strings.TrimSpace(n.Version) != ""
+	},
+	"file-name": func(_ *CheckContext, n *Node, _ []string) bool {
+		return strings.TrimSpace(n.FileName) != "" || (n.Type == Node_FILE && n.Name != "")
+	},
+	"suppliers": func(_ *CheckContext, n *Node, _ []string) bool {
+		return hasPersonName(n.Suppliers)
+	},
+	"creators": func(_ *CheckContext, n *Node, _ []string) bool {
+		return hasPersonName(n.Suppliers) || hasPersonName(n.Originators)
+	},
+	"identifiers": func(_ *CheckContext, n *Node, _ []string) bool {
+		return hasUniqueIdentifier(n)
+	},
+	"relationships": func(ctx *CheckContext, n *Node, _ []string) bool {
+		return ctx.IsRelated(n.Id)
+	},
+	// hashes checks if the node has hashes. If values are specified, at least
+	// one of the hashes must use one of the listed algorithms.
+	"hashes": func(_ *CheckContext, n *Node, values []string) bool {
+		for algo, value := range n.Hashes {
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			if len(values) == 0 {
+				return true
+			}
+			for _, v := range values {
+				if NormalizeHashAlgorithm(v) == NormalizeHashAlgorithm(algo) {
+					return true
+				}
+			}
+		}
+		return false
+	},
+	"licenses": func(_ *CheckContext, n *Node, _ []string) bool {
+		for _, l := range n.Licenses {
+			if strings.TrimSpace(l) != "" {
+				return true
+			}
+		}
+		return strings.TrimSpace(n.LicenseConcluded) != ""
+	},
+	"copyright": func(_ *CheckContext, n *Node, _ []string) bool {
+		c := strings.TrimSpace(n.Copyright)
+		return c != "" && c != "NOASSERTION"
+	},
+	"url-download": func(_ *CheckContext, n *Node, _ []string) bool {
+		return strings.TrimSpace(n.UrlDownload) != ""
+	},
+	"url-home": func(_ *CheckContext, n *Node, _ []string) bool {
+		return strings.TrimSpace(n.UrlHome) != ""
+	},
+	// properties checks the node declares what kind of artifact it is
+	// (executable, archive, structured) through its file types or purpose.
+	"properties": func(_ *CheckContext, n *Node, _ []string) bool {
+		return len(n.FileTypes) > 0 || strings.TrimSpace(n.PrimaryPurpose) != ""
+	},
+	// external-references checks for references of the listed types, or
+	// of any type if no values are specified.
+	"external-references": func(_ *CheckContext, n *Node, values []string) bool {
+		for _, er := range n.ExternalReferences {
+			if strings.TrimSpace(er.Url) == "" {
+				continue
+			}
+			if len(values) == 0 {
+				return true
+			}
+			for _, v := range values {
+				if strings.EqualFold(v, er.Type) {
+					return true
+				}
+			}
+		}
+		return false
+	},
+}
+
+// Profiles are the built in compliance profiles, keyed by their ID
+var Profiles = map[string]*Profile{
+	NTIAProfile.ID:        NTIAProfile,
+	BSITR03183Profile.ID:  BSITR03183Profile,
+	CISAFramingProfile.ID: CISAFramingProfile,
+}
+
+// NTIAProfile checks the NTIA minimum elements. File nodes are not considered
+// components by the NTIA guidance so they are not checked.
+var NTIAProfile = &Profile{
+	ID:          "ntia",
+	Name:        "NTIA",
+	Description: "The Minimum Elements For a Software Bill of Materials (NTIA, July 2021)",
+	Requirements: []*Requirement{
+		{ID: "NTIA-1", Element: NTIASupplierName, Scope: ScopeNode, Field: "suppliers", NodeTypes: []string{"PACKAGE"}},
+		{ID: "NTIA-2", Element: NTIAComponentName, Scope: ScopeNode, Field: "name", NodeTypes: []string{"PACKAGE"}},
+		{ID: "NTIA-3", Element: NTIAComponentVersion, Scope: ScopeNode, Field: "version", NodeTypes: []string{"PACKAGE"}},
+		{ID: "NTIA-4", Element: NTIAUniqueIdentifiers, Scope: ScopeNode, Field: "identifiers", NodeTypes: []string{"PACKAGE"}},
+		{ID: "NTIA-5", Element: NTIADependencyRelationship, Scope: ScopeNode, Field: "relationships", NodeTypes: []string{"PACKAGE"}},
+		{ID: "NTIA-6", Element: NTIAAuthor, Scope: ScopeDocument, Field: "authors"},
+		{ID: "NTIA-7", Element: NTIATimestamp, Scope: ScopeDocument, Field: "timestamp"},
+	},
+}
+
+// BSITR03183Profile checks the data fields required by the German Federal
+// Office for Information Security technical guideline TR-03183-2 (v1.1).
+var BSITR03183Profile = &Profile{
+	ID:          "bsi-tr-03183",
+	Name:        "BSI TR-03183-2",
+	Description: "BSI Technical Guideline TR-03183 Part 2: Software Bill of Materials (v1.1)",
+	Requirements: []*Requirement{
+		{ID: "5.2.1-creator", Element: "creator of the sbom", Scope: ScopeDocument, Field: "authors-contact"},
+		{ID: "5.2.1-timestamp", Element: "timestamp", Scope: ScopeDocument, Field: "timestamp"},
+		{ID: "5.2.2-creator", Element: "component creator", Scope: ScopeNode, Field: "creators"},
+		{ID: "5.2.2-name", Element: "component name", Scope: ScopeNode, Field: "name"},
+		{ID: "5.2.2-version", Element: "component version", Scope: ScopeNode, Field: "version", NodeTypes: []string{"PACKAGE"}},
+		{ID: "5.2.2-filename", Element: "filename of the component", Scope: ScopeNode, Field: "file-name"},
+		{ID: "5.2.2-dependencies", Element: "dependencies on other components", Scope: ScopeNode, Field: "relationships"},
+		{ID: "5.2.2-licenses", Element: "associated licences", Scope: ScopeNode, Field: "licenses"},
+		{ID: "5.2.2-hash", Element: "hash value of the deployable component", Scope: ScopeNode, Field: "hashes", Values: []string{"SHA512"}},
+		{ID: "5.2.2-properties", Element: "executable, archive and structured properties", Scope: ScopeNode, Field: "properties"},
+		{ID: "5.2.3-sbom-uri", Element: "sbom uri", Scope: ScopeDocument, Field: "id", Optional: true},
+		{ID: "5.2.4-source-uri", Element: "source code uri", Scope: ScopeNode, Field: "external-references", Values: []string{"vcs", "source-distribution"}, NodeTypes: []string{"PACKAGE"}, Optional: true},
+		{ID: "5.2.4-deployable-uri", Element: "uri of the deployable form", Scope: ScopeNode, Field: "url-download", NodeTypes: []string{"PACKAGE"}, Optional: true},
+		{ID: "5.2.4-identifiers", Element: "other unique identifiers", Scope: ScopeNode, Field: "identifiers", NodeTypes: []string{"PACKAGE"}, Optional: true},
+	},
+}
+
+// CISAFramingProfile checks the baseline attributes defined in the CISA
+// "Framing Software Component Transparency" document (2024 edition).
+var CISAFramingProfile = &Profile{
+	ID:          "cisa-framing",
+	Name:        "CISA Framing",
+	Description: "Framing Software Component Transparency: Establishing a Common Software Bill of Materials (CISA, 2024)",
+	Requirements: []*Requirement{
+		{ID: "sbom-author", Element: "sbom author", Scope: ScopeDocument, Field: "authors"},
+		{ID: "timestamp", Element: "timestamp", Scope: ScopeDocument, Field: "timestamp"},
+		{ID: "primary-component", Element: "primary component", Scope: ScopeDocument, Field: "root-elements"},
+		{ID: "component-name", Element: "component name", Scope: ScopeNode, Field: "name", NodeTypes: []string{"PACKAGE"}},
+		{ID: "version", Element: "version", Scope: ScopeNode, Field: "version", NodeTypes: []string{"PACKAGE"}},
+		{ID: "supplier-name", Element: "supplier name", Scope: ScopeNode, Field: "suppliers", NodeTypes: []string{"PACKAGE"}},
+		{ID: "unique-identifiers", Element: "unique identifiers", Scope: ScopeNode, Field: "identifiers", NodeTypes: []string{"PACKAGE"}},
+		{ID: "cryptographic-hash", Element: "cryptographic hash", Scope: ScopeNode, Field: "hashes", NodeTypes: []string{"PACKAGE"}},
+		{ID: "relationships", Element: "relationships", Scope: ScopeNode, Field: "relationships", NodeTypes: []string{"PACKAGE"}},
+		{ID: "license", Element: "license", Scope: ScopeNode, Field: "licenses", NodeTypes: []string{"PACKAGE"}},
+		{ID: "copyright-holder", Element: "copyright holder", Scope: ScopeNode, Field: "copyright", NodeTypes: []string{"PACKAGE"}, Optional: true},
+	},
+}
+
+// LoadProfile reads a compliance profile definition in JSON and verifies
+// all its requirements reference known field checks.
+func LoadProfile(r io.Reader) (*Profile, error) {
+	p := &Profile{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, fmt.Errorf("decoding profile: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	return p, nil
+}
+
+// GetProfile returns one of the built in profiles by its ID
+func GetProfile(id string) (*Profile, error) {
+	p, ok := Profiles[strings.ToLower(id)]
+	if !ok {
+		ids := []string{}
+		for k := range Profiles {
+			ids = append(ids, k)
+		}
+		sort.Strings(ids)
+		return nil, fmt.Errorf("unknown profile %q, available profiles: %s", id, strings.Join(ids, ", "))
+	}
+	return p, nil
+}
+
+// Validate checks the profile definition is well formed
+func (p *Profile) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("profile has no ID")
+	}
+	for i, req := range p.Requirements {
+		switch req.Scope {
+		case ScopeDocument:
+			if _, ok := DocumentFieldChecks[req.Field]; !ok {
+				return fmt.Errorf("requirement #%d (%s): unknown document field %q", i, req.ID, req.Field)
+			}
+		case ScopeNode:
+			if _, ok := NodeFieldChecks[req.Field]; !ok {
+				return fmt.Errorf("requirement #%d (%s): unknown node field %q", i, req.ID, req.Field)
+			}
+		default:
+			return fmt.Errorf("requirement #%d (%s): invalid scope %q", i, req.ID, req.Scope)
+		}
+	}
+	return nil
+}
+
+// appliesTo returns true if the requirement should be checked on a node
+func (req *Requirement) appliesTo(n *Node) bool {
+	if len(req.NodeTypes) == 0 {
+		return true
+	}
+	for _, t := range req.NodeTypes {
+		if strings.EqualFold(t, n.Type.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check verifies the document against the profile requirements and returns
+// a report with the coverage of each element and the gaps found.
+func (p *Profile) Check(doc *Document) *ComplianceReport {
+	report := &ComplianceReport{
+		Profile:      p.Name,
+		ProfileID:    p.ID,
+		Coverage:     []*ElementCoverage{},
+		DocumentGaps: []string{},
+		NodeGaps:     map[string][]string{},
+	}
+	ctx := &CheckContext{Document: doc}
+
+	for _, req := range p.Requirements {
+		cov := &ElementCoverage{ID: req.ID, Element: req.Element, Required: !req.Optional}
+		switch req.Scope {
+		case ScopeDocument:
+			check, ok := DocumentFieldChecks[req.Field]
+			if !ok {
+				continue
+			}
+			cov.Total = 1
+			if check(doc, req.Values) {
+				cov.Covered = 1
+			} else {
+				report.DocumentGaps = append(report.DocumentGaps, req.Element)
+			}
+		case ScopeNode:
+			check, ok := NodeFieldChecks[req.Field]
+			if !ok {
+				continue
+			}
+			for _, n := range doc.GetNodes() {
+				if !req.appliesTo(n) {
+					continue
+				}
+				cov.Total++
+				if check(ctx, n, req.Values) {
+					cov.Covered++
+					continue
+				}
+				report.NodeGaps[n.Id] = append(report.NodeGaps[n.Id], req.Element)
+			}
+		default:
+			continue
+		}
+		report.Coverage = append(report.Coverage, cov)
+	}
+	return report
+}
+
+// Rules returns the profile requirements as validation rules so they can be
+// checked together with other rules using ValidateWithRules.
+func (p *Profile) Rules() []*ValidationRule {
+	rules := []*ValidationRule{}
+	for _, req := range p.Requirements {
+		req := req
+		severity := SeverityError
+		if req.Optional {
+			severity = SeverityWarning
+		}
+		rules = append(rules, &ValidationRule{
+			ID:          fmt.Sprintf("%s:%s", p.ID, req.ID),
+			Description: fmt.Sprintf("%s: %s", p.Name, req.Element),
+			Severity:    severity,
+			Check: func(doc *Document) []*Finding {
+				single := &Profile{ID: p.ID, Name: p.Name, Requirements: []*Requirement{req}}
+				return single.Check(doc).Findings()
+			},
+		})
+	}
+	return rules
+}
+
+func hasPersonName(people []*Person) bool {
+	for _, p := range people {
+		if strings.TrimSpace(p.Name) != "" {
+			return true
+		}
+	}
+	return false
+}
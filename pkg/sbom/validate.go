@@ -0,0 +1,454 @@
+package sbom
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Severity indicates how serious a validation finding is
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the name of the severity level
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// Finding is a problem detected when checking a document against a rule. It
+// optionally points to the node or edge where the problem was found.
+type Finding struct {
+	RuleID   string
+	Severity Severity
+	Message  string
+	NodeID   string
+	Edge     *Edge
+}
+
+// String returns a human readable representation of the finding
+func (f *Finding) String() string {
+	ref := ""
+	switch {
+	case f.NodeID != "":
+		ref = fmt.Sprintf(" [node %s]", f.NodeID)
+	case f.Edge != nil:
+		ref = fmt.Sprintf(" [edge %s %s %v]", f.Edge.From, f.Edge.Type, f.Edge.To)
+	}
+	return fmt.Sprintf("%s %s: %s%s", f.Severity, f.RuleID, f.Message, ref)
+}
+
+// Findings is a list of validation findings
+type Findings []*Finding
+
+// Filter returns the findings with a severity equal or higher than s
+func (fs Findings) Filter(s Severity) Findings {
+	ret := Findings{}
+	for _, f := range fs {
+		if f.Severity >= s {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// HasErrors returns true if any of the findings has error severity
+func (fs Findings) HasErrors() bool {
+	return len(fs.Filter(SeverityError)) > 0
+}
+
+// ValidationRule is a check performed on a document. The check function
+// returns the problems it finds, the validator then stamps them with the
+// rule ID and severity.
+type ValidationRule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Check       func(*Document) []*Finding
+}
+
+// ValidationRules is the list of rules applied by Document.Validate to check
+// the internal consistency of a document.
+var ValidationRules = []*ValidationRule{
+	{
+		ID:          "PB001",
+		Description: "Edges must originate in an existing node",
+		Severity:    SeverityError,
+		Check:       checkEdgeOrigins,
+	},
+	{
+		ID:          "PB002",
+		Description: "Edges must point to existing nodes",
+		Severity:    SeverityError,
+		Check:       checkEdgeDestinations,
+	},
+	{
+		ID:          "PB003",
+		Description: "Documents should have root elements",
+		Severity:    SeverityWarning,
+		Check:       checkRootsDefined,
+	},
+	{
+		ID:          "PB004",
+		Description: "Root elements must point to existing nodes",
+		Severity:    SeverityError,
+		Check:       checkRootsExist,
+	},
+	{
+		ID:          "PB005",
+		Description: "Node IDs must not be empty",
+		Severity:    SeverityError,
+		Check:       checkEmptyIDs,
+	},
+	{
+		ID:          "PB006",
+		Description: "Node IDs must be unique",
+		Severity:    SeverityError,
+		Check:       checkDuplicateIDs,
+	},
+	{
+		ID:          "PB007",
+		Description: "Nodes must have a name",
+		Severity:    SeverityError,
+		Check:       checkEmptyNames,
+	},
+	{
+		ID:          "PB008",
+		Description: "Hash values must be well formed for their algorithm",
+		Severity:    SeverityError,
+		Check:       checkHashValues,
+	},
+	{
+		ID:          "PB009",
+		Description: "Hash algorithms should be known",
+		Severity:    SeverityWarning,
+		Check:       checkHashAlgorithms,
+	},
+	{
+		ID:          "PB010",
+		Description: "Timestamps must be valid",
+		Severity:    SeverityError,
+		Check:       checkTimestamps,
+	},
+	{
+		ID:          "PB011",
+		Description: "Edges should have a known type",
+		Severity:    SeverityWarning,
+		Check:       checkEdgeTypes,
+	},
+	{
+		ID:          "PB012",
+		Description: "Edges should have at least one destination",
+		Severity:    SeverityWarning,
+		Check:       checkEdgeDestinationsDefined,
+	},
+	{
+		ID:          "PB013",
+		Description: "Vulnerabilities must affect existing nodes",
+		Severity:    SeverityError,
+		Check:       checkVulnerabilityAffects,
+	},
+}
+
+// hashLengths maps the normalized hash algorithm names to the length of
+// their hex encoded values.
+var hashLengths = map[string]int{
+	"MD2":        32,
+	"MD4":        32,
+	"MD5":        32,
+	"MD6":        0, // MD6 has a variable length
+	"SHA1":       40,
+	"SHA224":     56,
+	"SHA256":     64,
+	"SHA384":     96,
+	"SHA512":     128,
+	"SHA3256":    64,
+	"SHA3384":    96,
+	"SHA3512":    128,
+	"BLAKE2B256": 64,
+	"BLAKE2B384": 96,
+	"BLAKE2B512": 128,
+	"BLAKE3":     0, // BLAKE3 has a variable length
+	"ADLER32":    8,
+}
+
+// Validate checks the document for internal consistency using the default
+// validation rules and returns the list of problems found.
+func (x *Document) Validate() Findings {
+	return ValidateWithRules(x, ValidationRules)
+}
+
+// ValidateWithRules checks a document against a custom set of rules
+func ValidateWithRules(doc *Document, rules []*ValidationRule) Findings {
+	findings := Findings{}
+	if doc == nil {
+		return append(findings, &Finding{
+			RuleID: "PB000", Severity: SeverityError, Message: "document is nil",
+		})
+	}
+	for _, rule := range rules {
+		for _, f := range rule.Check(doc) {
+			f.RuleID = rule.ID
+			f.Severity = rule.Severity
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// nodeIndex returns a set of the IDs of all nodes in the document
+func (x *Document) nodeIndex() map[string]struct{} {
+	index := map[string]struct{}{}
+	for _, n := range x.Nodes {
+		index[n.Id] = struct{}{}
+	}
+	return index
+}
+
+// GetNodeByID returns the node with the specified ID or nil if not found
+func (x *Document) GetNodeByID(id string) *Node {
+	for _, n := range x.GetNodes() {
+		if n.Id == id {
+			return n
+		}
+	}
+	return nil
+}
+
+func checkEdgeOrigins(doc *Document) []*Finding {
+	index := doc.nodeIndex()
+	ret := []*Finding{}
+	for _, e := range doc.Edges {
+		if _, ok := index[e.From]; !ok {
+			ret = append(ret, &Finding{
+				Message: fmt.Sprintf("edge origin %q does not exist", e.From),
+				Edge:    e,
+			})
+		}
+	}
+	return ret
+}
+
+func checkEdgeDestinations(doc *Document) []*Finding {
+	index := doc.nodeIndex()
+	ret := []*Finding{}
+	for _, e := range doc.Edges {
+		for _, to := range e.To {
+			if _, ok := index[to]; !ok {
+				ret = append(ret, &Finding{
+					Message: fmt.Sprintf("edge destination %q does not exist", to),
+					Edge:    e,
+				})
+			}
+		}
+	}
+	return ret
+}
+
+func checkRootsDefined(doc *Document) []*Finding {
+	if len(doc.RootElements) > 0 {
+		return nil
+	}
+	return []*Finding{{Message: "document has no root elements"}}
+}
+
+func checkRootsExist(doc *Document) []*Finding {
+	index := doc.nodeIndex()
+	ret := []*Finding{}
+	for _, id := range doc.RootElements {
+		if _, ok := index[id]; !ok {
+			ret = append(ret, &Finding{
+				Message: fmt.Sprintf("root element %q does not exist", id),
+				NodeID:  id,
+			})
+		}
+	}
+	return ret
+}
+
+func checkEmptyIDs(doc *Document) []*Finding {
+	ret := []*Finding{}
+	for i, n := range doc.Nodes {
+		if strings.TrimSpace(n.Id) == "" {
+			ret = append(ret, &Finding{
+				Message: fmt.Sprintf("node #%d (%q) has no ID", i, n.Name),
+			})
+		}
+	}
+	return ret
+}
+
+func checkDuplicateIDs(doc *Document) []*Finding {
+	seen := map[string]int{}
+	ret := []*Finding{}
+	for _, n := range doc.Nodes {
+		if n.Id == "" {
+			continue
+		}
+		seen[n.Id]++
+		if seen[n.Id] == 2 {
+			ret = append(ret, &Finding{
+				Message: fmt.Sprintf("node ID %q is not unique", n.Id),
+				NodeID:  n.Id,
+			})
+		}
+	}
+	return ret
+}
+
+func checkEmptyNames(doc *Document) []*Finding {
+	ret := []*Finding{}
+	for _, n := range doc.Nodes {
+		if strings.TrimSpace(n.Name) == "" {
+			ret = append(ret, &Finding{
+				Message: "node has no name",
+				NodeID:  n.Id,
+			})
+		}
+	}
+	return ret
+}
+
+func checkHashValues(doc *Document) []*Finding {
+	ret := []*Finding{}
+	for _, n := range doc.Nodes {
+		for algo, value := range n.Hashes {
+			if _, err := hex.DecodeString(value); err != nil || value == "" {
+				ret = append(ret, &Finding{
+					Message: fmt.Sprintf("%s hash value %q is not a hex string", algo, value),
+					NodeID:  n.Id,
+				})
+				continue
+			}
+			l, ok := hashLengths[NormalizeHashAlgorithm(algo)]
+			if ok && l != 0 && len(value) != l {
+				ret = append(ret, &Finding{
+					Message: fmt.Sprintf(
+						"%s hash value has %d characters, expected %d", algo, len(value), l,
+					),
+					NodeID: n.Id,
+				})
+			}
+		}
+	}
+	return ret
+}
+
+func checkHashAlgorithms(doc *Document) []*Finding {
+	ret := []*Finding{}
+	for _, n := range doc.Nodes {
+		for algo := range n.Hashes {
+			if _, ok := hashLengths[NormalizeHashAlgorithm(algo)]; !ok {
+				ret = append(ret, &Finding{
+					Message: fmt.Sprintf("unknown hash algorithm %q", algo),
+					NodeID:  n.Id,
+				})
+			}
+		}
+	}
+	return ret
+}
+
+// isTimestampSet returns true when a timestamp is not nil or zero. Zero
+// timestamps are used by the parsers when data is missing.
+func isTimestampSet(ts *timestamppb.Timestamp) bool {
+	return ts != nil && (ts.Seconds != 0 || ts.Nanos != 0)
+}
+
+func checkTimestamps(doc *Document) []*Finding {
+	ret := []*Finding{}
+	if isTimestampSet(doc.GetMetadata().GetDate()) {
+		if err := doc.Metadata.Date.CheckValid(); err != nil {
+			ret = append(ret, &Finding{
+				Message: fmt.Sprintf("document date is invalid: %v", err),
+			})
+		}
+	}
+
+	for _, n := range doc.Nodes {
+		for _, ts := range []struct {
+			label string
+			value *timestamppb.Timestamp
+		}{
+			{"release date", n.ReleaseDate},
+			{"build date", n.BuildDate},
+			{"valid until date", n.ValidUntilDate},
+		} {
+			if !isTimestampSet(ts.value) {
+				continue
+			}
+			if err := ts.value.CheckValid(); err != nil {
+				ret = append(ret, &Finding{
+					Message: fmt.Sprintf("%s is invalid: %v", ts.label, err),
+					NodeID:  n.Id,
+				})
+			}
+		}
+
+		if isTimestampSet(n.ReleaseDate) && isTimestampSet(n.ValidUntilDate) &&
+			n.ValidUntilDate.AsTime().Before(n.ReleaseDate.AsTime()) {
+			ret = append(ret, &Finding{
+				Message: "valid until date is earlier than the release date",
+				NodeID:  n.Id,
+			})
+		}
+	}
+	return ret
+}
+
+func checkEdgeTypes(doc *Document) []*Finding {
+	ret := []*Finding{}
+	for _, e := range doc.Edges {
+		if e.Type == Edge_UNKNOWN {
+			ret = append(ret, &Finding{
+				Message: "edge has an unknown relationship type",
+				Edge:    e,
+			})
+		}
+	}
+	return ret
+}
+
+func checkEdgeDestinationsDefined(doc *Document) []*Finding {
+	ret := []*Finding{}
+	for _, e := range doc.Edges {
+		if len(e.To) == 0 {
+			ret = append(ret, &Finding{
+				Message: "edge has no destination nodes",
+				Edge:    e,
+			})
+		}
+	}
+	return ret
+}
+
+func checkVulnerabilityAffects(doc *Document) []*Finding {
+	index := doc.nodeIndex()
+	ret := []*Finding{}
+	for _, v := range doc.Vulnerabilities {
+		for _, a := range v.Affects {
+			if _, ok := index[a.NodeId]; !ok {
+				ret = append(ret, &Finding{
+					Message: fmt.Sprintf("vulnerability %s affects missing node %q", v.Id, a.NodeId),
+					NodeID:  a.NodeId,
+				})
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vex
+
+import (
+	"fmt"
+
+	"github.com/puerco/protobom/pkg/purl"
+	"github.com/puerco/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statusToState maps the OpenVEX statuses to the protobom analysis states
+var statusToState = map[string]sbom.Analysis_State{
+	StatusNotAffected:        sbom.Analysis_notAffected,
+	StatusAffected:           sbom.Analysis_exploitable,
+	StatusFixed:              sbom.Analysis_resolved,
+	StatusUnderInvestigation: sbom.Analysis_inTriage,
+}
+
+// justificationToProto maps the OpenVEX justifications to protobom. The
+// mapping follows the CISA "Minimum Requirements for VEX" translation table,
+// component_not_present has its own value so it survives a round trip.
+var justificationToProto = map[string]sbom.Analysis_Justification{
+	JustificationComponentNotPresent:                         sbom.Analysis_componentNotPresent,
+	JustificationVulnerableCodeNotPresent:                    sbom.Analysis_codeNotPresent,
+	JustificationVulnerableCodeNotInExecutePath:              sbom.Analysis_codeNotReachable,
+	JustificationVulnerableCodeCannotBeControlledByAdversary: sbom.Analysis_requiresEnvironment,
+	JustificationInlineMitigationsAlreadyExist:               sbom.Analysis_protectedByMitigatingControl,
+}
+
+// ApplyResult summarizes the application of a VEX document to an SBOM
+type ApplyResult struct {
+	// Applied is the number of statements that matched at least one node
+	Applied int
+
+	// Unmatched lists the statements that did not match any node
+	Unmatched []*Statement
+}
+
+// Apply records the statements in the VEX document as vulnerability analysis
+// data in the matching nodes of the SBOM. Products and subcomponents are
+// matched to nodes by package URL. When a statement lists subcomponents,
+// those are the affected nodes, otherwise the products are. Statements are
+// applied in chronological order so newer ones supersede older ones.
+func Apply(doc *sbom.Document, vexDoc *Document) (*ApplyResult, error) {
+	if doc == nil || vexDoc == nil {
+		return nil, fmt.Errorf("unable to apply VEX data, document is nil")
+	}
+
+	index := newNodeIndex(doc)
+	result := &ApplyResult{Unmatched: []*Statement{}}
+	for _, s := range vexDoc.SortedStatements() {
+		if s.Vulnerability.Name == "" {
+			return nil, fmt.Errorf("VEX statement has no vulnerability name")
+		}
+
+		nodeIDs := index.match(s)
+		if len(nodeIDs) == 0 {
+			result.Unmatched = append(result.Unmatched, s)
+			continue
+		}
+
+		applyStatement(doc, vexDoc, s, nodeIDs)
+		result.Applied++
+	}
+	return result, nil
+}
+
+// applyStatement moves the nodes affected by the statement to a vulnerability
+// entry carrying the statement analysis.
+func applyStatement(doc *sbom.Document, vexDoc *Document, s *Statement, nodeIDs []string) {
+	analysis := statementToAnalysis(vexDoc, s)
+	affected := map[string]struct{}{}
+	for _, id := range nodeIDs {
+		affected[id] = struct{}{}
+	}
+
+	// Remove the nodes from any previous entry of the same vulnerability,
+	// reusing its data (ratings, description, etc) for the new entry.
+	var template *sbom.Vulnerability
+	var target *sbom.Vulnerability
+	vulns := []*sbom.Vulnerability{}
+	for _, v := range doc.Vulnerabilities {
+		if !sameVulnerability(v, &s.Vulnerability) {
+			vulns = append(vulns, v)
+			continue
+		}
+		if template == nil {
+			template = v
+		}
+		if target == nil && proto.Equal(v.Analysis, analysis) && v.Recommendation == s.ActionStatement {
+			target = v
+		}
+
+		before := len(v.Affects)
+		affects := []*sbom.Affects{}
+		for _, a := range v.Affects {
+			if _, ok := affected[a.NodeId]; !ok || v == target {
+				affects = append(affects, a)
+			}
+		}
+		v.Affects = affects
+
+		// Drop entries left without nodes by this statement
+		if before > 0 && len(v.Affects) == 0 && v != target {
+			continue
+		}
+		vulns = append(vulns, v)
+	}
+	doc.Vulnerabilities = vulns
+
+	if target == nil {
+		target = &sbom.Vulnerability{
+			Id:      s.Vulnerability.Name,
+			Aliases: s.Vulnerability.Aliases,
+		}
+		if template != nil {
+			if clone, ok := proto.Clone(template).(*sbom.Vulnerability); ok {
+				target = clone
+			}
+		}
+		target.Affects = []*sbom.Affects{}
+		target.Analysis = analysis
+		target.Recommendation = s.ActionStatement
+		if target.Description == "" {
+			target.Description = s.Vulnerability.Description
+		}
+		doc.Vulnerabilities = append(doc.Vulnerabilities, target)
+	}
+
+	present := map[string]struct{}{}
+	for _, a := range target.Affects {
+		present[a.NodeId] = struct{}{}
+	}
+	for _, id := range nodeIDs {
+		if _, ok := present[id]; ok {
+			continue
+		}
+		target.Affects = append(target.Affects, &sbom.Affects{NodeId: id})
+	}
+}
+
+// sameVulnerability returns true if a protobom vulnerability and a VEX
+// vulnerability refer to the same issue, either by ID or by their aliases.
+func sameVulnerability(v *sbom.Vulnerability, vv *Vulnerability) bool {
+	ids := append([]string{vv.Name}, vv.Aliases...)
+	for _, id := range ids {
+		if v.Id == id {
+			return true
+		}
+		for _, alias := range v.Aliases {
+			if alias == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statementToAnalysis translates the statement status to a protobom analysis
+func statementToAnalysis(vexDoc *Document, s *Statement) *sbom.Analysis {
+	a := &sbom.Analysis{
+		State:         statusToState[s.Status],
+		Justification: justificationToProto[s.Justification],
+		Response:      []sbom.Analysis_Response{},
+		Detail:        s.ImpactStatement,
+	}
+
+	if a.Detail == "" {
+		a.Detail = s.StatusNotes
+	}
+
+	if vexDoc.Timestamp != nil {
+		a.FirstIssued = timestamppb.New(*vexDoc.Timestamp)
+	}
+
+	switch {
+	case s.Timestamp != nil:
+		a.LastUpdated = timestamppb.New(*s.Timestamp)
+	case vexDoc.LastUpdated != nil:
+		a.LastUpdated = timestamppb.New(*vexDoc.LastUpdated)
+	case vexDoc.Timestamp != nil:
+		a.LastUpdated = timestamppb.New(*vexDoc.Timestamp)
+	}
+	return a
+}
+
+// nodeIndex looks up nodes by their package URL or ID
+type nodeIndex struct {
+	ids   map[string]struct{}
+	purls map[string]*purl.PackageURL
+	order []string
+}
+
+func newNodeIndex(doc *sbom.Document) *nodeIndex {
+	idx := &nodeIndex{
+		ids:   map[string]struct{}{},
+		purls: map[string]*purl.PackageURL{},
+		order: []string{},
+	}
+	for _, n := range doc.Nodes {
+		idx.ids[n.Id] = struct{}{}
+		idx.order = append(idx.order, n.Id)
+		if p, err := purl.Parse(n.Purl()); err == nil {
+			idx.purls[n.Id] = p
+		}
+	}
+	return idx
+}
+
+// find returns the IDs of the nodes matching a component identifier
+func (idx *nodeIndex) find(iri string) []string {
+	if _, ok := idx.ids[iri]; ok {
+		return []string{iri}
+	}
+	pattern, err := purl.Parse(iri)
+	if err != nil {
+		return nil
+	}
+	ret := []string{}
+	for _, id := range idx.order {
+		if p, ok := idx.purls[id]; ok && pattern.Matches(p) {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+// match returns the IDs of the nodes a statement applies to
+func (idx *nodeIndex) match(s *Statement) []string {
+	seen := map[string]struct{}{}
+	ret := []string{}
+	add := func(ids []string) {
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ret = append(ret, id)
+		}
+	}
+
+	for _, p := range s.Products {
+		components := p.Subcomponents
+		if len(components) == 0 {
+			components = []*Component{p}
+		}
+		for _, c := range components {
+			for _, iri := range c.Purls() {
+				add(idx.find(iri))
+			}
+		}
+	}
+	return ret
+}
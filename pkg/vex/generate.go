@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// DefaultActionStatement is used in affected statements when the
+// vulnerability has no recommendation, the spec requires one.
+const DefaultActionStatement = "No remediation is available yet"
+
+// stateToStatus maps the protobom analysis states to OpenVEX statuses
+var stateToStatus = map[sbom.Analysis_State]string{
+	sbom.Analysis_notAffected:          StatusNotAffected,
+	sbom.Analysis_falsePositive:        StatusNotAffected,
+	sbom.Analysis_exploitable:          StatusAffected,
+	sbom.Analysis_resolved:             StatusFixed,
+	sbom.Analysis_resolvedWithPedigree: StatusFixed,
+	sbom.Analysis_inTriage:             StatusUnderInvestigation,
+}
+
+// justificationFromProto maps the protobom justifications to OpenVEX
+var justificationFromProto = map[sbom.Analysis_Justification]string{
+	sbom.Analysis_componentNotPresent:          JustificationComponentNotPresent,
+	sbom.Analysis_codeNotPresent:               JustificationVulnerableCodeNotPresent,
+	sbom.Analysis_codeNotReachable:             JustificationVulnerableCodeNotInExecutePath,
+	sbom.Analysis_requiresConfiguration:        JustificationVulnerableCodeCannotBeControlledByAdversary,
+	sbom.Analysis_requiresDependency:           JustificationVulnerableCodeCannotBeControlledByAdversary,
+	sbom.Analysis_requiresEnvironment:          JustificationVulnerableCodeCannotBeControlledByAdversary,
+	sbom.Analysis_protectedByCompiler:          JustificationInlineMitigationsAlreadyExist,
+	sbom.Analysis_protectedAtRuntime:           JustificationInlineMitigationsAlreadyExist,
+	sbom.Analysis_protectedAtPerimeter:         JustificationInlineMitigationsAlreadyExist,
+	sbom.Analysis_protectedByMitigatingControl: JustificationInlineMitigationsAlreadyExist,
+}
+
+// Options controls the metadata of generated VEX documents
+type Options struct {
+	// ID of the document. When empty, an ID is derived from the SBOM digest.
+	ID string
+
+	// Author and Role of the document author
+	Author string
+	Role   string
+
+	// Tooling records the tool generating the document
+	Tooling string
+
+	// Timestamp of the document, defaults to the current time
+	Timestamp *time.Time
+}
+
+// Generate builds an OpenVEX document from the vulnerability analysis data
+// attached to an SBOM. Each vulnerability with analysis data becomes a
+// statement. Affected nodes are listed as subcomponents of the document's
+// root elements when those can be identified by package URL.
+func Generate(doc *sbom.Document, opts *Options) (*Document, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("unable to generate VEX data, document is nil")
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	ts := time.Now().UTC()
+	if opts.Timestamp != nil {
+		ts = *opts.Timestamp
+	}
+
+	vexDoc := &Document{
+		Context:    Context,
+		ID:         opts.ID,
+		Author:     opts.Author,
+		Role:       opts.Role,
+		Tooling:    opts.Tooling,
+		Timestamp:  &ts,
+		Version:    1,
+		Statements: []*Statement{},
+	}
+
+	if vexDoc.Author == "" {
+		vexDoc.Author = "Unknown Author"
+	}
+
+	if vexDoc.ID == "" {
+		digest, err := doc.Digest(&sbom.DigestOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("computing document digest: %w", err)
+		}
+		vexDoc.ID = "https://openvex.dev/docs/public/vex-" + strings.TrimPrefix(digest, sbom.DigestPrefix)
+	}
+
+	roots := map[string]string{}
+	for _, id := range doc.RootElements {
+		if n := doc.GetNodeByID(id); n != nil {
+			roots[id] = n.Purl()
+		}
+	}
+
+	paths := doc.RootPaths()
+	for _, v := range doc.Vulnerabilities {
+		if v.Analysis == nil {
+			continue
+		}
+		s, err := vulnerabilityToStatement(doc, roots, paths, v)
+		if err != nil {
+			return nil, fmt.Errorf("translating %s: %w", v.Id, err)
+		}
+		if s != nil {
+			vexDoc.Statements = append(vexDoc.Statements, s)
+		}
+	}
+	return vexDoc, nil
+}
+
+// vulnerabilityToStatement converts a protobom vulnerability to a VEX
+// statement. It returns nil if the vulnerability does not affect any node.
+func vulnerabilityToStatement(doc *sbom.Document, roots map[string]string, paths *sbom.RootPaths, v *sbom.Vulnerability) (*Statement, error) {
+	status, ok := stateToStatus[v.Analysis.State]
+	if !ok {
+		status = StatusUnderInvestigation
+	}
+
+	s := &Statement{
+		Vulnerability: Vulnerability{
+			Name:        v.Id,
+			Description: v.Description,
+			Aliases:     v.Aliases,
+		},
+		Products: []*Component{},
+		Status:   status,
+	}
+
+	if v.Analysis.LastUpdated != nil {
+		t := v.Analysis.LastUpdated.AsTime()
+		s.Timestamp = &t
+	}
+
+	switch status {
+	case StatusNotAffected:
+		s.Justification = justificationFromProto[v.Analysis.Justification]
+		s.ImpactStatement = v.Analysis.Detail
+		if s.Justification == "" && s.ImpactStatement == "" {
+			return nil, fmt.Errorf("not_affected statements require a justification or impact statement")
+		}
+	case StatusAffected:
+		s.ActionStatement = v.Recommendation
+		if s.ActionStatement == "" {
+			s.ActionStatement = DefaultActionStatement
+		}
+		s.StatusNotes = v.Analysis.Detail
+	default:
+		s.StatusNotes = v.Analysis.Detail
+	}
+
+	// Group the affected nodes under the root product they are reached from
+	// when it carries a purl. Products listed without subcomponents are
+	// affected themselves.
+	products := map[string]*Component{}
+	parents := map[string]*Component{}
+	for _, a := range v.Affects {
+		n := doc.GetNodeByID(a.NodeId)
+		if n == nil {
+			return nil, fmt.Errorf("affected node %s not found", a.NodeId)
+		}
+		iri := n.Purl()
+		if iri == "" {
+			iri = n.Id
+		}
+
+		if _, isRoot := roots[n.Id]; isRoot {
+			if _, ok := products[iri]; !ok {
+				products[iri] = &Component{ID: iri}
+				s.Products = append(s.Products, products[iri])
+			}
+			continue
+		}
+
+		if rootPurl := roots[paths.PathTo(n.Id)[0]]; rootPurl != "" {
+			p, ok := parents[rootPurl]
+			if !ok {
+				p = &Component{ID: rootPurl}
+				parents[rootPurl] = p
+				s.Products = append(s.Products, p)
+			}
+			p.Subcomponents = append(p.Subcomponents, &Component{ID: iri})
+			continue
+		}
+
+		if _, ok := products[iri]; !ok {
+			products[iri] = &Component{ID: iri}
+			s.Products = append(s.Products, products[iri])
+		}
+	}
+
+	if len(s.Products) == 0 {
+		return nil, nil
+	}
+	return s, nil
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: Copyright 2023 The StarBOM Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vex reads and writes OpenVEX documents and translates their
+// statements to and from the vulnerability data in protobom documents.
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// Context is the OpenVEX JSON-LD context written in new documents
+	Context = "https://openvex.dev/ns/v0.2.0"
+
+	StatusNotAffected        = "not_affected"
+	StatusAffected           = "affected"
+	StatusFixed              = "fixed"
+	StatusUnderInvestigation = "under_investigation"
+
+	JustificationComponentNotPresent                         = "component_not_present"
+	JustificationVulnerableCodeNotPresent                    = "vulnerable_code_not_present"
+	JustificationVulnerableCodeNotInExecutePath              = "vulnerable_code_not_in_execute_path"
+	JustificationVulnerableCodeCannotBeControlledByAdversary = "vulnerable_code_cannot_be_controlled_by_adversary"
+	JustificationInlineMitigationsAlreadyExist               = "inline_mitigations_already_exist"
+)
+
+// Document is an OpenVEX document
+type Document struct {
+	Context     string       `json:"@context"`
+	ID          string       `json:"@id"`
+	Author      string       `json:"author"`
+	Role        string       `json:"role,omitempty"`
+	Timestamp   *time.Time   `json:"timestamp"`
+	LastUpdated *time.Time   `json:"last_updated,omitempty"`
+	Version     int          `json:"version"`
+	Tooling     string       `json:"tooling,omitempty"`
+	Statements  []*Statement `json:"statements"`
+}
+
+// Statement asserts the status of a vulnerability in a set of products
+type Statement struct {
+	ID              string        `json:"@id,omitempty"`
+	Vulnerability   Vulnerability `json:"vulnerability"`
+	Timestamp       *time.Time    `json:"timestamp,omitempty"`
+	Products        []*Component  `json:"products"`
+	Status          string        `json:"status"`
+	StatusNotes     string        `json:"status_notes,omitempty"`
+	Justification   string        `json:"justification,omitempty"`
+	ImpactStatement string        `json:"impact_statement,omitempty"`
+	ActionStatement string        `json:"action_statement,omitempty"`
+
+	// Subcomponents is only present in OpenVEX v0.0.1 documents, newer
+	// versions list the subcomponents inside each product.
+	Subcomponents []*Component `json:"subcomponents,omitempty"`
+}
+
+// Vulnerability identifies the vulnerability a statement refers to
+type Vulnerability struct {
+	ID          string   `json:"@id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// UnmarshalJSON reads the vulnerability either as an object or as a plain
+// string, as written by OpenVEX v0.0.1.
+func (v *Vulnerability) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		v.Name = name
+		return nil
+	}
+	type vulnerability Vulnerability
+	return json.Unmarshal(data, (*vulnerability)(v))
+}
+
+// Component is a product or subcomponent, identified by its IRI (usually a
+// package URL) or its identifiers and hashes.
+type Component struct {
+	ID            string            `json:"@id,omitempty"`
+	Identifiers   map[string]string `json:"identifiers,omitempty"`
+	Hashes        map[string]string `json:"hashes,omitempty"`
+	Subcomponents []*Component      `json:"subcomponents,omitempty"`
+}
+
+// UnmarshalJSON reads the component either as an object or as a plain
+// string, as written by OpenVEX v0.0.1.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		c.ID = id
+		return nil
+	}
+	type component Component
+	return json.Unmarshal(data, (*component)(c))
+}
+
+// Purls returns the package URLs identifying the component
+func (c *Component) Purls() []string {
+	ret := []string{}
+	if c.ID != "" {
+		ret = append(ret, c.ID)
+	}
+	if p, ok := c.Identifiers["purl"]; ok && p != c.ID {
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// Parse reads an OpenVEX document from a stream
+func Parse(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("decoding OpenVEX document: %w", err)
+	}
+
+	// Move the v0.0.1 top level subcomponents into the products
+	for _, s := range doc.Statements {
+		if len(s.Subcomponents) == 0 {
+			continue
+		}
+		for _, p := range s.Products {
+			p.Subcomponents = append(p.Subcomponents, s.Subcomponents...)
+		}
+		s.Subcomponents = nil
+	}
+	return doc, nil
+}
+
+// ParseFile reads an OpenVEX document from a file
+func ParseFile(path string) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening VEX document: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Write serializes the document as JSON
+func (d *Document) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("encoding OpenVEX document: %w", err)
+	}
+	return nil
+}
+
+// SortedStatements returns the statements of the document ordered by their
+// timestamp. Statements without a timestamp inherit the document's.
+func (d *Document) SortedStatements() []*Statement {
+	ret := make([]*Statement, len(d.Statements))
+	copy(ret, d.Statements)
+	ts := func(s *Statement) time.Time {
+		switch {
+		case s.Timestamp != nil:
+			return *s.Timestamp
+		case d.Timestamp != nil:
+			return *d.Timestamp
+		default:
+			return time.Time{}
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ts(ret[i]).Before(ts(ret[j]))
+	})
+	return ret
+}
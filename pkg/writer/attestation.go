@@ -0,0 +1,57 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/puerco/protobom/pkg/attestation"
+	"github.com/puerco/protobom/pkg/sbom"
+	"github.com/puerco/protobom/pkg/writer/options"
+)
+
+// predicateTypes are the in-toto predicate types of the output formats
+var predicateTypes = map[formats.Format]string{
+	formats.SPDX23JSON: attestation.PredicateTypeSPDX + "/v2.3",
+	formats.CDX14JSON:  attestation.PredicateTypeCycloneDX + "/v1.4",
+}
+
+// writeAttestation serializes the document as the predicate of an in-toto
+// statement, wrapped in an unsigned DSSE envelope if set in the options.
+func (w *Writer) writeAttestation(bom *sbom.Document, wr io.Writer, deg Degradation) error {
+	predicateType, ok := predicateTypes[w.Options.Format]
+	if !ok {
+		return fmt.Errorf("no in-toto predicate type defined for %s", w.Options.Format)
+	}
+
+	subjects := attestation.SubjectsFromDocument(bom)
+	if len(subjects) == 0 {
+		return errors.New("document has no subjects, the root nodes have no hashes")
+	}
+
+	predicate := bytes.Buffer{}
+	if err := w.serialize(bom, nopCloser{&predicate}, deg); err != nil {
+		return err
+	}
+
+	statement := attestation.NewStatement(predicateType, subjects, predicate.Bytes())
+	var v interface{} = statement
+	if w.Options.Attestation == options.AttestationEnvelope {
+		env, err := attestation.NewEnvelope(statement)
+		if err != nil {
+			return fmt.Errorf("building envelope: %w", err)
+		}
+		v = env
+	}
+
+	enc := json.NewEncoder(wr)
+	enc.SetIndent("", strings.Repeat(" ", w.Options.Indent))
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding attestation: %w", err)
+	}
+	return nil
+}
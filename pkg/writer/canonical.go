@@ -0,0 +1,102 @@
+package writer
+
+import (
+	"sort"
+
+	cdx14 "github.com/onesbom/onesbom/pkg/formats/cyclonedx/v14"
+	"github.com/puerco/protobom/pkg/formats/cyclonedx"
+)
+
+// canonicalizeCDX14 sorts all lists in a CycloneDX document to ensure the
+// serialized output is always the same for the same data.
+func canonicalizeCDX14(doc *cdx14.Document) {
+	canonicalizeCDX14Component(&doc.Metadata.Component)
+	canonicalizeCDX14Components(doc.Components)
+
+	// Merge the dependency lists of repeated refs before sorting them
+	deps := map[string][]string{}
+	for _, d := range doc.Dependencies {
+		deps[d.Ref] = append(deps[d.Ref], d.DependsOn...)
+	}
+
+	if doc.Dependencies != nil {
+		doc.Dependencies = []cdx14.Dependency{}
+	}
+	for ref, dependsOn := range deps {
+		doc.Dependencies = append(doc.Dependencies, cdx14.Dependency{
+			Ref:       ref,
+			DependsOn: dedupeSorted(dependsOn),
+		})
+	}
+	sort.Slice(doc.Dependencies, func(i, j int) bool {
+		return doc.Dependencies[i].Ref < doc.Dependencies[j].Ref
+	})
+}
+
+// canonicalizeCDX14Components sorts a list of components and all their
+// children recursively.
+func canonicalizeCDX14Components(components []cdx14.Component) {
+	for i := range components {
+		canonicalizeCDX14Component(&components[i])
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		if components[i].Ref != components[j].Ref {
+			return components[i].Ref < components[j].Ref
+		}
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+// canonicalizeCDX14Component sorts the lists in a single component
+func canonicalizeCDX14Component(c *cdx14.Component) {
+	sort.Slice(c.Hashes, func(i, j int) bool {
+		if c.Hashes[i].Algorithm != c.Hashes[j].Algorithm {
+			return c.Hashes[i].Algorithm < c.Hashes[j].Algorithm
+		}
+		return c.Hashes[i].Content < c.Hashes[j].Content
+	})
+
+	sort.Slice(c.Licenses, func(i, j int) bool {
+		return c.Licenses[i].License.ID < c.Licenses[j].License.ID
+	})
+
+	sort.Slice(c.ExternalReferences, func(i, j int) bool {
+		if c.ExternalReferences[i].Type != c.ExternalReferences[j].Type {
+			return c.ExternalReferences[i].Type < c.ExternalReferences[j].Type
+		}
+		return c.ExternalReferences[i].URL < c.ExternalReferences[j].URL
+	})
+
+	canonicalizeCDX14Components(c.Components)
+}
+
+// canonicalizeCDX14Vulnerabilities sorts the vulnerabilities by ID and the
+// components they affect by their ref.
+func canonicalizeCDX14Vulnerabilities(vulns []cyclonedx.Vulnerability) {
+	for i := range vulns {
+		sort.Slice(vulns[i].Affects, func(a, b int) bool {
+			return vulns[i].Affects[a].Ref < vulns[i].Affects[b].Ref
+		})
+	}
+	sort.SliceStable(vulns, func(i, j int) bool {
+		return vulns[i].ID < vulns[j].ID
+	})
+}
+
+// dedupeSorted returns a sorted copy of a string slice without duplicates
+func dedupeSorted(list []string) []string {
+	seen := map[string]struct{}{}
+	ret := []string{}
+	for _, s := range list {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		ret = append(ret, s)
+	}
+	sort.Strings(ret)
+	return ret
+}
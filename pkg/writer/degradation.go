@@ -0,0 +1,63 @@
+package writer
+
+import "sort"
+
+// Kinds of data degraded when serializing a document
+const (
+	// DegradationRootElements counts root elements written as plain
+	// components because the format describes a single one
+	DegradationRootElements = "root elements"
+
+	// DegradationRelationships counts relationships of types the format
+	// can't express, dropped or written with a generic type
+	DegradationRelationships = "relationships"
+
+	// DegradationNesting counts contains relationships that could not be
+	// expressed by nesting the components
+	DegradationNesting = "nesting"
+
+	// DegradationVulnerabilities counts vulnerabilities dropped because the
+	// format does not support them
+	DegradationVulnerabilities = "vulnerabilities"
+
+	// DegradationProperties counts node properties dropped because the
+	// format has no place for them
+	DegradationProperties = "properties"
+)
+
+// Degradation counts, by kind, the data of a document that was lost or
+// altered because the output format can't represent it.
+type Degradation map[string]int
+
+// add records n degraded elements of a kind
+func (d Degradation) add(kind string, n int) {
+	if d != nil && n > 0 {
+		d[kind] += n
+	}
+}
+
+// Merge adds the counts of another degradation
+func (d Degradation) Merge(other Degradation) {
+	for kind, n := range other {
+		d.add(kind, n)
+	}
+}
+
+// Total returns the number of degraded elements
+func (d Degradation) Total() int {
+	total := 0
+	for _, n := range d {
+		total += n
+	}
+	return total
+}
+
+// Kinds returns the degraded kinds, sorted
+func (d Degradation) Kinds() []string {
+	kinds := []string{}
+	for kind := range d {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
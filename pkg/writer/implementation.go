@@ -2,6 +2,7 @@ package writer
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,14 +11,16 @@ import (
 
 	"github.com/onesbom/onesbom/pkg/formats"
 	cdx14 "github.com/onesbom/onesbom/pkg/formats/cyclonedx/v14"
+	"github.com/puerco/protobom/pkg/formats/cyclonedx"
 	"github.com/puerco/protobom/pkg/sbom"
 	"github.com/puerco/protobom/pkg/writer/options"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 )
 
 type writerImplementation interface {
 	GetFormatSerializer(formats.Format) (Serializer, error)
-	SerializeSBOM(options.Options, Serializer, *sbom.Document, io.WriteCloser) error
+	SerializeSBOM(options.Options, Serializer, *sbom.Document, io.WriteCloser, Degradation) error
 	OpenFile(string) (*os.File, error)
 }
 
@@ -34,29 +37,40 @@ func (di *defaultWriterImplementation) GetFormatSerializer(format formats.Format
 }
 
 // SerializeSBOM takes an SBOM in protobuf and a serializer and uses it to render
-// the document into the serializer format.
-func (di *defaultWriterImplementation) SerializeSBOM(opts options.Options, s Serializer, bom *sbom.Document, wr io.WriteCloser) error {
+// the document into the serializer format. The data that can't be rendered
+// is counted in deg.
+func (di *defaultWriterImplementation) SerializeSBOM(opts options.Options, s Serializer, bom *sbom.Document, wr io.WriteCloser, deg Degradation) error {
 	ver, err := strconv.Atoi(bom.Metadata.Version)
 	if err != nil {
 		ver = 0
 	}
-	doc := cdx14.Document{
-		Version:      ver,
-		Format:       "CycloneDX",
-		SpecVersion:  "1.4",
-		SerialNumber: bom.Metadata.Id,
-		Metadata: cdx14.Metadata{
-			// Tools:     []cdx14.Tool{},
-			Component: cdx14.Component{},
+	doc := cyclonedx.Document{
+		Document: cdx14.Document{
+			Version:      ver,
+			Format:       "CycloneDX",
+			SpecVersion:  "1.4",
+			SerialNumber: bom.Metadata.Id,
+			Metadata: cdx14.Metadata{
+				// Tools:     []cdx14.Tool{},
+				Component: cdx14.Component{},
+			},
+			Components:   []cdx14.Component{},
+			Dependencies: []cdx14.Dependency{},
 		},
-		Components:   []cdx14.Component{},
-		Dependencies: []cdx14.Dependency{},
 	}
-	/*
-		if bom.Metadata.Date != nil {
-			doc.Metadata.Timestamp = bom.Metadata.Date.AsTime()
+
+	if bom.Metadata.Date != nil {
+		doc.Metadata.Timestamp = bom.Metadata.Date.AsTime()
+	}
+
+	if opts.Canonical {
+		var ok bool
+		bom, ok = proto.Clone(bom).(*sbom.Document)
+		if !ok {
+			return errors.New("unable to copy document to canonicalize")
 		}
-	*/
+		bom.Canonicalize()
+	}
 
 	// Generate all components
 	components := map[string]*cdx14.Component{}
@@ -67,6 +81,7 @@ func (di *defaultWriterImplementation) SerializeSBOM(opts options.Options, s Ser
 			// Erorr? Warrn?
 			continue
 		}
+		deg.add(DegradationProperties, len(n.Properties))
 
 		if comp.Ref == "" {
 			refless = append(refless, comp)
@@ -91,81 +106,94 @@ func (di *defaultWriterImplementation) SerializeSBOM(opts options.Options, s Ser
 				}
 			}
 
-			// TODO(degradation): Here we would document other root level elements
-			// are not added to to document
+			// The other root level elements are written as components
+			deg.add(DegradationRootElements, len(bom.RootElements)-1)
 			break
 		}
 	}
 
 	// Next up. Let's navigate the SBOM graph and translate it to the CDX simpler
 	// tree or to the dependency graph
+	children := map[string][]string{}
 	for _, e := range bom.Edges {
-		if _, ok := addedDict[e.From]; ok {
-			continue
-		}
-
-		if _, ok := components[e.From]; !ok {
+		_, isRoot := rootDict[e.From]
+		if _, ok := components[e.From]; !ok && !isRoot {
 			logrus.Info("serialize")
 			return fmt.Errorf("unable to find component %s", e.From)
 		}
 
+		for _, targetID := range e.To {
+			if _, ok := components[targetID]; !ok {
+				return fmt.Errorf("unable to locate node %s", targetID)
+			}
+		}
+
 		// In this example, we tree-ify all components related with a
 		// "contains" relationship. This is just an opinion for the demo
 		// and it is somethign we can parameterize
 		switch e.Type {
 		case sbom.Edge_contains:
-			// Make sure we have the target component
+			// The top level components are already contained in the root
+			if isRoot {
+				continue
+			}
 			for _, targetID := range e.To {
 				addedDict[targetID] = struct{}{}
-				if _, ok := components[targetID]; !ok {
-					return fmt.Errorf("unable to locate node %s", targetID)
-				}
-
-				if components[e.From].Components == nil {
-					components[e.From].Components = []cdx14.Component{}
-				}
-				components[e.From].Components = append(components[e.From].Components, *components[targetID])
 			}
+			children[e.From] = append(children[e.From], e.To...)
 
 		case sbom.Edge_dependsOn:
 			// Add to the dependency tree
-			for _, targetID := range e.To {
-				addedDict[targetID] = struct{}{}
-				if _, ok := components[targetID]; !ok {
-					return fmt.Errorf("unable to locate node %s", targetID)
-				}
-
-				if doc.Dependencies == nil {
-					doc.Dependencies = []cdx14.Dependency{}
-				}
-
-				doc.Dependencies = append(doc.Dependencies, cdx14.Dependency{
-					Ref:       e.From,
-					DependsOn: e.To,
-				})
+			if doc.Dependencies == nil {
+				doc.Dependencies = []cdx14.Dependency{}
 			}
 
+			doc.Dependencies = append(doc.Dependencies, cdx14.Dependency{
+				Ref:       e.From,
+				DependsOn: e.To,
+			})
+
 		default:
-			// TODO(degradation) here, we would document how relationships are lost
+			deg.add(DegradationRelationships, len(e.To))
 			logrus.Warnf(
 				"node %s is related with %s to %d other nodes, data will be lost",
 				e.From, e.Type, len(e.To),
 			)
 		}
+	}
 
-		// Now add al nodes we have not yet positioned
-		for _, c := range components {
-			if _, ok := addedDict[c.Ref]; ok {
-				continue
-			}
-			doc.Components = append(doc.Components, *c)
+	// Nest the contained components once all the edges are known so that
+	// the full tree is copied regardless of the edge order.
+	for ref, c := range components {
+		if _, ok := addedDict[ref]; ok {
+			continue
 		}
+		nestCDX14Components(c, components, children, map[string]struct{}{ref: {}})
+	}
 
-		// Add components without refs
-		for _, c := range refless {
-			doc.Components = append(doc.Components, *c)
+	// Now add al nodes we have not yet positioned
+	for _, c := range components {
+		if _, ok := addedDict[c.Ref]; ok {
+			continue
 		}
+		doc.Components = append(doc.Components, *c)
+	}
+
+	// Add components without refs
+	for _, c := range refless {
+		doc.Components = append(doc.Components, *c)
+	}
+
+	// Vulnerabilities reference the affected components by their bom-ref
+	for _, v := range bom.Vulnerabilities {
+		doc.Vulnerabilities = append(doc.Vulnerabilities, *cyclonedx.VulnerabilityFromProto(v))
+	}
+
+	if opts.Canonical {
+		canonicalizeCDX14(&doc.Document)
+		canonicalizeCDX14Vulnerabilities(doc.Vulnerabilities)
 	}
+
 	logrus.Info("Writing SBOM in CycloneDX to STDOUT")
 	encoder := json.NewEncoder(wr)
 	encoder.SetIndent("", strings.Repeat(" ", opts.Indent))
@@ -224,6 +252,12 @@ func nodeToCDX14Component(n *sbom.Node) *cdx14.Component {
 		}
 	}
 
+	for _, i := range n.Identifiers {
+		if i.Type == "purl" && c.Purl == "" {
+			c.Purl = i.Value
+		}
+	}
+
 	if n.ExternalReferences != nil {
 		for _, er := range n.ExternalReferences {
 			if er.Type == "purl" {
@@ -245,11 +279,33 @@ func nodeToCDX14Component(n *sbom.Node) *cdx14.Component {
 	return c
 }
 
-// OpenFile opens the file at path and returns it
+// OpenFile creates or truncates the file at path and returns it
 func (di *defaultWriterImplementation) OpenFile(path string) (*os.File, error) {
-	f, err := os.Open(path)
+	f, err := os.Create(path)
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
 	}
 	return f, nil
 }
+
+// nestCDX14Components copies the components contained in c into it,
+// recursively. Cycles in the contains edges are cut when a component is
+// already an ancestor.
+func nestCDX14Components(
+	c *cdx14.Component, components map[string]*cdx14.Component,
+	children map[string][]string, ancestors map[string]struct{},
+) {
+	for _, id := range children[c.Ref] {
+		if _, ok := ancestors[id]; ok {
+			continue
+		}
+		child := *components[id]
+		ancestors[id] = struct{}{}
+		nestCDX14Components(&child, components, children, ancestors)
+		delete(ancestors, id)
+		if c.Components == nil {
+			c.Components = []cdx14.Component{}
+		}
+		c.Components = append(c.Components, child)
+	}
+}
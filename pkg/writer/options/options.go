@@ -5,8 +5,34 @@ import "github.com/onesbom/onesbom/pkg/formats"
 type Options struct {
 	Format formats.Format
 	Indent int
+
+	// Canonical sorts all elements in the output to make the serialized
+	// document deterministic. Two runs over the same data will produce
+	// byte-for-byte identical documents.
+	Canonical bool
+
+	// Compression is the algorithm used to compress the output, only
+	// CompressionGzip is supported.
+	Compression string
+
+	// Attestation wraps the document in an in-toto statement or in a DSSE
+	// envelope containing the statement.
+	Attestation string
 }
 
+// Compression algorithms of the output
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+)
+
+// Attestation wrappers of the output
+const (
+	AttestationNone      = ""
+	AttestationStatement = "in-toto"
+	AttestationEnvelope  = "dsse"
+)
+
 var Default = Options{
 	Indent: 4,
 	Format: "application/vnd.cyclonedx+json;version=1.4",
@@ -0,0 +1,303 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/onesbom/onesbom/pkg/formats"
+	"github.com/puerco/protobom/pkg/sbom"
+	"github.com/puerco/protobom/pkg/writer/options"
+	"google.golang.org/protobuf/proto"
+)
+
+// streamEncoder serializes the elements of a document in a format
+type streamEncoder interface {
+	begin(md *sbom.Metadata, rootElements []string) error
+	node(*sbom.Node) error
+	edge(*sbom.Edge) error
+	finish() error
+
+	// abort releases the resources of an unfinished document
+	abort()
+}
+
+const (
+	phaseNodes = iota
+	phaseEdges
+	phaseFinished
+)
+
+// StreamWriter serializes a document incrementally. The document is
+// started with its metadata, then all the nodes are written followed by
+// the edges. Finish must be called to complete the document, or Close to
+// abandon it.
+type StreamWriter struct {
+	enc   streamEncoder
+	phase int
+	out   io.Closer
+	deg   Degradation
+}
+
+// Begin starts writing a document to wr in the format of the writer
+// options. The root elements are the IDs of the nodes the document
+// describes. The output is compressed if set in the options.
+func (w *Writer) Begin(md *sbom.Metadata, rootElements []string, wr io.Writer) (*StreamWriter, error) {
+	if w.Options.Attestation != options.AttestationNone {
+		return nil, errors.New("documents wrapped in attestations can't be streamed")
+	}
+	out, err := compressWriter(w.Options.Compression, wr)
+	if err != nil {
+		return nil, err
+	}
+	sw, err := w.begin(md, rootElements, out)
+	if err != nil {
+		return nil, err
+	}
+	sw.out = out
+	return sw, nil
+}
+
+// begin starts writing an uncompressed document to wr
+func (w *Writer) begin(md *sbom.Metadata, rootElements []string, wr io.Writer) (*StreamWriter, error) {
+	if md == nil {
+		md = &sbom.Metadata{}
+	}
+	jw := &jsonWriter{
+		w:      bufio.NewWriter(wr),
+		indent: strings.Repeat(" ", w.Options.Indent),
+	}
+
+	deg := Degradation{}
+	var enc streamEncoder
+	switch w.Options.Format {
+	case formats.CDX14JSON:
+		enc = &cdx14StreamEncoder{jw: jw, deg: deg}
+	case formats.SPDX23JSON:
+		enc = &spdx23StreamEncoder{jw: jw, deg: deg}
+	default:
+		return nil, fmt.Errorf("no streaming serializer supports rendering to %s", w.Options.Format)
+	}
+
+	if err := enc.begin(md, rootElements); err != nil {
+		return nil, fmt.Errorf("starting document: %w", err)
+	}
+	return &StreamWriter{enc: enc, deg: deg}, nil
+}
+
+// WriteNode writes a node to the document. Nodes can't be written after
+// the first edge.
+func (sw *StreamWriter) WriteNode(n *sbom.Node) error {
+	if sw.phase != phaseNodes {
+		return errors.New("nodes must be written before the edges")
+	}
+	if err := sw.enc.node(n); err != nil {
+		return fmt.Errorf("writing node %s: %w", n.Id, err)
+	}
+	return nil
+}
+
+// WriteEdge writes an edge to the document
+func (sw *StreamWriter) WriteEdge(e *sbom.Edge) error {
+	if sw.phase == phaseFinished {
+		return errors.New("document is already finished")
+	}
+	sw.phase = phaseEdges
+	if err := sw.enc.edge(e); err != nil {
+		return fmt.Errorf("writing edge from %s: %w", e.From, err)
+	}
+	return nil
+}
+
+// Finish completes the document and flushes it to the writer
+func (sw *StreamWriter) Finish() error {
+	if sw.phase == phaseFinished {
+		return errors.New("document is already finished")
+	}
+	sw.phase = phaseFinished
+	if err := sw.enc.finish(); err != nil {
+		sw.enc.abort()
+		return fmt.Errorf("finishing document: %w", err)
+	}
+	if sw.out != nil {
+		if err := sw.out.Close(); err != nil {
+			return fmt.Errorf("closing compressed stream: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close abandons a document that is not finished, removing the temporary
+// data spooled to write it. The output is left incomplete. Closing a
+// finished document does nothing.
+func (sw *StreamWriter) Close() error {
+	if sw.phase == phaseFinished {
+		return nil
+	}
+	sw.phase = phaseFinished
+	sw.enc.abort()
+	return nil
+}
+
+// Degradation returns the data degraded so far in the output format
+func (sw *StreamWriter) Degradation() Degradation {
+	return sw.deg
+}
+
+// writeStreamed serializes a complete document through a stream writer
+func (w *Writer) writeStreamed(bom *sbom.Document, wr io.Writer, deg Degradation) error {
+	if w.Options.Canonical {
+		var ok bool
+		bom, ok = proto.Clone(bom).(*sbom.Document)
+		if !ok {
+			return errors.New("unable to copy document to canonicalize")
+		}
+		bom.Canonicalize()
+	}
+
+	sw, err := w.begin(bom.Metadata, bom.RootElements, wr)
+	if err != nil {
+		return err
+	}
+	defer sw.Close()
+	for _, n := range bom.Nodes {
+		if err := sw.WriteNode(n); err != nil {
+			return err
+		}
+	}
+	for _, e := range bom.Edges {
+		if err := sw.WriteEdge(e); err != nil {
+			return err
+		}
+	}
+	if err := sw.Finish(); err != nil {
+		return err
+	}
+	deg.Merge(sw.deg)
+	deg.add(DegradationVulnerabilities, len(bom.Vulnerabilities))
+	return nil
+}
+
+// jsonWriter writes a JSON object one field or array item at a time. The
+// object has one level of arrays, items are indented two levels deep.
+type jsonWriter struct {
+	w      *bufio.Writer
+	indent string
+	fields int
+	items  int
+	err    error
+}
+
+func (jw *jsonWriter) raw(s string) {
+	if jw.err == nil {
+		_, jw.err = jw.w.WriteString(s)
+	}
+}
+
+// newline starts a new line at the indentation level when indenting
+func (jw *jsonWriter) newline(level int) {
+	if jw.indent != "" {
+		jw.raw("\n" + strings.Repeat(jw.indent, level))
+	}
+}
+
+func (jw *jsonWriter) marshal(v interface{}, level int) {
+	if jw.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		jw.err = err
+		return
+	}
+	jw.rawValue(data, level)
+}
+
+// rawValue writes encoded JSON, indenting it to the level
+func (jw *jsonWriter) rawValue(data []byte, level int) {
+	if jw.err != nil {
+		return
+	}
+	if jw.indent != "" {
+		buf := bytes.Buffer{}
+		if err := json.Indent(&buf, data, strings.Repeat(jw.indent, level), jw.indent); err != nil {
+			jw.err = err
+			return
+		}
+		data = buf.Bytes()
+	}
+	_, jw.err = jw.w.Write(data)
+}
+
+func (jw *jsonWriter) key(name string) {
+	if jw.fields > 0 {
+		jw.raw(",")
+	}
+	jw.fields++
+	jw.newline(1)
+	jw.marshal(name, 0)
+	if jw.indent != "" {
+		jw.raw(": ")
+	} else {
+		jw.raw(":")
+	}
+}
+
+// open starts the top level object
+func (jw *jsonWriter) open() {
+	jw.raw("{")
+}
+
+// field writes a complete field of the object
+func (jw *jsonWriter) field(name string, v interface{}) {
+	jw.key(name)
+	jw.marshal(v, 1)
+}
+
+// openArray starts an array field
+func (jw *jsonWriter) openArray(name string) {
+	jw.key(name)
+	jw.raw("[")
+	jw.items = 0
+}
+
+// item writes a value in the open array
+func (jw *jsonWriter) item(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil && jw.err == nil {
+		jw.err = err
+	}
+	jw.rawItem(data)
+}
+
+// rawItem writes an encoded value in the open array
+func (jw *jsonWriter) rawItem(data []byte) {
+	if jw.items > 0 {
+		jw.raw(",")
+	}
+	jw.items++
+	jw.newline(2)
+	jw.rawValue(data, 2)
+}
+
+func (jw *jsonWriter) closeArray() {
+	if jw.items > 0 {
+		jw.newline(1)
+	}
+	jw.raw("]")
+	jw.items = 0
+}
+
+// close ends the object and flushes the output
+func (jw *jsonWriter) close() error {
+	jw.newline(0)
+	jw.raw("}\n")
+	if jw.err != nil {
+		return jw.err
+	}
+	return jw.w.Flush()
+}
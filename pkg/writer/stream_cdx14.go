@@ -0,0 +1,114 @@
+package writer
+
+import (
+	"strconv"
+
+	cdx14 "github.com/onesbom/onesbom/pkg/formats/cyclonedx/v14"
+	"github.com/puerco/protobom/pkg/sbom"
+	"github.com/sirupsen/logrus"
+)
+
+// cdx14StreamEncoder writes CycloneDX 1.4 JSON incrementally. Components
+// can't be nested without knowing the whole graph so they are written as
+// a flat list, the contains edges are dropped. The first root element is
+// written as the metadata component after the components, JSON objects
+// are unordered.
+type cdx14StreamEncoder struct {
+	jw        *jsonWriter
+	deg       Degradation
+	metadata  *sbom.Metadata
+	root      string
+	rootComp  *cdx14.Component
+	inEdges   bool
+	nContains int
+}
+
+func (e *cdx14StreamEncoder) begin(md *sbom.Metadata, rootElements []string) error {
+	e.metadata = md
+	// Other root elements are written as components
+	if len(rootElements) > 0 {
+		e.root = rootElements[0]
+		e.deg.add(DegradationRootElements, len(rootElements)-1)
+	}
+
+	ver, err := strconv.Atoi(md.Version)
+	if err != nil {
+		ver = 0
+	}
+	e.jw.open()
+	e.jw.field("version", ver)
+	e.jw.field("bomFormat", "CycloneDX")
+	e.jw.field("specVersion", "1.4")
+	e.jw.field("serialNumber", md.Id)
+	e.jw.openArray("components")
+	return e.jw.err
+}
+
+func (e *cdx14StreamEncoder) node(n *sbom.Node) error {
+	comp := nodeToCDX14Component(n)
+	if comp == nil {
+		return nil
+	}
+	e.deg.add(DegradationProperties, len(n.Properties))
+	if n.Id == e.root && e.rootComp == nil {
+		e.rootComp = comp
+		return nil
+	}
+	e.jw.item(comp)
+	return e.jw.err
+}
+
+// startEdges closes the components and opens the dependencies
+func (e *cdx14StreamEncoder) startEdges() {
+	if e.inEdges {
+		return
+	}
+	e.inEdges = true
+	e.jw.closeArray()
+	e.jw.openArray("dependencies")
+}
+
+func (e *cdx14StreamEncoder) edge(edge *sbom.Edge) error {
+	e.startEdges()
+	switch edge.Type {
+	case sbom.Edge_dependsOn:
+		e.jw.item(cdx14.Dependency{
+			Ref:       edge.From,
+			DependsOn: edge.To,
+		})
+	case sbom.Edge_contains:
+		e.nContains++
+		e.deg.add(DegradationNesting, len(edge.To))
+	default:
+		e.deg.add(DegradationRelationships, len(edge.To))
+		logrus.Warnf(
+			"node %s is related with %s to %d other nodes, data will be lost",
+			edge.From, edge.Type, len(edge.To),
+		)
+	}
+	return e.jw.err
+}
+
+func (e *cdx14StreamEncoder) abort() {}
+
+func (e *cdx14StreamEncoder) finish() error {
+	e.startEdges()
+	e.jw.closeArray()
+
+	if e.nContains > 0 {
+		logrus.Warnf(
+			"%d contains relationships can't be nested when streaming, components are written unnested",
+			e.nContains,
+		)
+	}
+
+	md := cdx14.Metadata{}
+	if e.metadata.Date != nil {
+		md.Timestamp = e.metadata.Date.AsTime()
+	}
+	if e.rootComp != nil {
+		md.Component = *e.rootComp
+	}
+	e.jw.field("metadata", md)
+	return e.jw.close()
+}
@@ -0,0 +1,372 @@
+package writer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onesbom/onesbom/pkg/formats/spdx"
+	spdx23 "github.com/onesbom/onesbom/pkg/formats/spdx/v23"
+	"github.com/puerco/protobom/pkg/sbom"
+)
+
+// spdxDateFormat is the format of the dates in SPDX documents
+const spdxDateFormat = "2006-01-02T15:04:05Z"
+
+// spdx23Package overrides the fields of the onesbom package that are not
+// encoded as the SPDX 2.3 JSON schema expects.
+type spdx23Package struct {
+	spdx23.Package
+	Filename       string `json:"packageFileName,omitempty"`
+	ReleaseDate    string `json:"releaseDate,omitempty"`
+	BuildDate      string `json:"builtDate,omitempty"`
+	ValidUntilDate string `json:"validUntilDate,omitempty"`
+}
+
+// spdx23StreamEncoder writes SPDX 2.3 JSON incrementally. Packages are
+// written as they arrive while files, which go in a separate array, are
+// spooled to a temporary file until the edges start.
+type spdx23StreamEncoder struct {
+	jw      *jsonWriter
+	deg     Degradation
+	spool   *os.File
+	spoolW  *bufio.Writer
+	inEdges bool
+}
+
+func (e *spdx23StreamEncoder) begin(md *sbom.Metadata, rootElements []string) error {
+	created := time.Now().UTC()
+	if md.Date != nil {
+		created = md.Date.AsTime().UTC()
+	}
+
+	creators := []string{}
+	for _, t := range md.Tools {
+		name := t.Name
+		if t.Version != "" {
+			name += "-" + t.Version
+		}
+		creators = append(creators, "Tool: "+name)
+	}
+	for _, a := range md.Authors {
+		creators = append(creators, personToActor(a))
+	}
+	if len(creators) == 0 {
+		creators = append(creators, "Tool: protobom")
+	}
+
+	describes := []string{}
+	for _, id := range rootElements {
+		describes = append(describes, spdx23.IDPrefix+id)
+	}
+
+	e.jw.open()
+	e.jw.field("spdxVersion", "SPDX-2.3")
+	e.jw.field("dataLicense", "CC0-1.0")
+	e.jw.field("SPDXID", spdx23.IDPrefix+"DOCUMENT")
+	e.jw.field("name", md.Name)
+	e.jw.field("documentNamespace", documentNamespace(md))
+	e.jw.field("creationInfo", spdx23.CreationInfo{
+		Created:  created.Format(spdxDateFormat),
+		Creators: creators,
+	})
+	e.jw.field("documentDescribes", describes)
+	e.jw.openArray("packages")
+	return e.jw.err
+}
+
+// documentNamespace returns the namespace of the document. The metadata ID
+// is used if it is a URI, otherwise a namespace is made up from the name
+// and ID of the document.
+func documentNamespace(md *sbom.Metadata) string {
+	if u, err := url.Parse(md.Id); err == nil && u.Scheme != "" && u.Host != "" {
+		return md.Id
+	}
+	name := md.Name
+	if name == "" {
+		name = "sbom"
+	}
+	ns := "https://spdx.org/spdxdocs/" + url.PathEscape(name)
+	if id := strings.TrimPrefix(md.Id, spdx23.IDPrefix); id != "" && id != "DOCUMENT" {
+		ns += "-" + url.PathEscape(id)
+	}
+	return ns
+}
+
+func (e *spdx23StreamEncoder) node(n *sbom.Node) error {
+	e.deg.add(DegradationProperties, len(n.Properties))
+	if n.Type == sbom.Node_PACKAGE {
+		e.jw.item(nodeToSPDX23Package(n))
+		return e.jw.err
+	}
+
+	if e.spool == nil {
+		f, err := os.CreateTemp("", "protobom-spdx-files-")
+		if err != nil {
+			return fmt.Errorf("creating files spool: %w", err)
+		}
+		e.spool = f
+		e.spoolW = bufio.NewWriter(f)
+	}
+	data, err := json.Marshal(nodeToSPDX23File(n))
+	if err != nil {
+		return fmt.Errorf("encoding file: %w", err)
+	}
+	if _, err := e.spoolW.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("spooling file: %w", err)
+	}
+	return nil
+}
+
+// startEdges closes the packages, writes the spooled files and opens the
+// relationships.
+func (e *spdx23StreamEncoder) startEdges() error {
+	if e.inEdges {
+		return nil
+	}
+	e.inEdges = true
+	e.jw.closeArray()
+
+	if e.spool != nil {
+		e.jw.openArray("files")
+		if err := e.copySpool(); err != nil {
+			return err
+		}
+		e.jw.closeArray()
+	}
+
+	e.jw.openArray("relationships")
+	return e.jw.err
+}
+
+// copySpool writes the spooled files to the files array
+func (e *spdx23StreamEncoder) copySpool() error {
+	defer e.cleanup()
+	if err := e.spoolW.Flush(); err != nil {
+		return fmt.Errorf("flushing files spool: %w", err)
+	}
+	if _, err := e.spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding files spool: %w", err)
+	}
+	dc := json.NewDecoder(bufio.NewReader(e.spool))
+	for {
+		raw := json.RawMessage{}
+		if err := dc.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return e.jw.err
+			}
+			return fmt.Errorf("reading files spool: %w", err)
+		}
+		e.jw.rawItem(raw)
+	}
+}
+
+// cleanup removes the files spool
+func (e *spdx23StreamEncoder) cleanup() {
+	if e.spool == nil {
+		return
+	}
+	e.spool.Close()
+	os.Remove(e.spool.Name())
+	e.spool = nil
+}
+
+func (e *spdx23StreamEncoder) edge(edge *sbom.Edge) error {
+	if err := e.startEdges(); err != nil {
+		return err
+	}
+	relType := sbom.EdgeTypeToSPDX(edge.Type)
+	if relType == "OTHER" && edge.Type != sbom.Edge_other {
+		e.deg.add(DegradationRelationships, len(edge.To))
+	}
+	for _, to := range edge.To {
+		e.jw.item(spdx23.Relationship{
+			Element: spdx23.IDPrefix + edge.From,
+			Type:    relType,
+			Related: spdx23.IDPrefix + to,
+		})
+	}
+	return e.jw.err
+}
+
+func (e *spdx23StreamEncoder) abort() {
+	e.cleanup()
+}
+
+func (e *spdx23StreamEncoder) finish() error {
+	if err := e.startEdges(); err != nil {
+		return err
+	}
+	e.jw.closeArray()
+	return e.jw.close()
+}
+
+// nodeToSPDX23Package converts a package node to an SPDX 2.3 package
+func nodeToSPDX23Package(n *sbom.Node) *spdx23Package {
+	p := &spdx23Package{
+		Package: spdx23.Package{
+			ID:               spdx23.IDPrefix + n.Id,
+			Name:             n.Name,
+			Version:          n.Version,
+			LicenseDeclared:  spdx.NOASSERTION,
+			LicenseConcluded: orNoAssertion(n.LicenseConcluded),
+			Description:      n.Description,
+			DownloadLocation: orNoAssertion(n.UrlDownload),
+			SourceInfo:       n.SourceInfo,
+			CopyrightText:    orNoAssertion(n.Copyright),
+			PrimaryPurpose:   n.PrimaryPurpose,
+			HomePage:         n.UrlHome,
+			Summary:          n.Summary,
+			Comment:          n.Comment,
+			Checksums:        spdx23Checksums(n.Hashes),
+		},
+		Filename: n.FileName,
+	}
+	if len(n.Licenses) > 0 {
+		p.LicenseDeclared = joinLicenses(n.Licenses)
+	}
+	if len(n.Attribution) > 0 {
+		p.Attribution = &n.Attribution
+	}
+	if len(n.Suppliers) > 0 {
+		p.Supplier = personToActor(n.Suppliers[0])
+	}
+	if len(n.Originators) > 0 {
+		p.Originator = personToActor(n.Originators[0])
+	}
+	if n.ReleaseDate != nil && n.ReleaseDate.AsTime().Unix() > 0 {
+		p.ReleaseDate = n.ReleaseDate.AsTime().UTC().Format(spdxDateFormat)
+	}
+	if n.BuildDate != nil && n.BuildDate.AsTime().Unix() > 0 {
+		p.BuildDate = n.BuildDate.AsTime().UTC().Format(spdxDateFormat)
+	}
+	if n.ValidUntilDate != nil && n.ValidUntilDate.AsTime().Unix() > 0 {
+		p.ValidUntilDate = n.ValidUntilDate.AsTime().UTC().Format(spdxDateFormat)
+	}
+
+	for _, i := range n.Identifiers {
+		p.ExternalRefs = append(p.ExternalRefs, identifierToExternalRef(i.Type, i.Value))
+	}
+	for _, er := range n.ExternalReferences {
+		p.ExternalRefs = append(p.ExternalRefs, identifierToExternalRef(er.Type, er.Url))
+	}
+	return p
+}
+
+// joinLicenses combines the licenses of a node in a single expression.
+// Compound expressions are grouped as AND binds tighter than OR.
+func joinLicenses(licenses []string) string {
+	if len(licenses) == 1 {
+		return licenses[0]
+	}
+	terms := []string{}
+	for _, l := range licenses {
+		if strings.Contains(strings.TrimSpace(l), " ") {
+			l = "(" + strings.TrimSpace(l) + ")"
+		}
+		terms = append(terms, l)
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// identifierToExternalRef returns the SPDX external reference of an
+// identifier or reference, categorized by its type.
+func identifierToExternalRef(refType, locator string) spdx23.ExternalRef {
+	category := "OTHER"
+	switch strings.ToLower(refType) {
+	case "purl":
+		category = "PACKAGE-MANAGER"
+		refType = "purl"
+	case "cpe22", "cpe22type":
+		category = "SECURITY"
+		refType = "cpe22Type"
+	case "cpe23", "cpe23type":
+		category = "SECURITY"
+		refType = "cpe23Type"
+	}
+	return spdx23.ExternalRef{Category: category, Type: refType, Locator: locator}
+}
+
+// nodeToSPDX23File converts a file node to an SPDX 2.3 file
+func nodeToSPDX23File(n *sbom.Node) *spdx23.File {
+	f := &spdx23.File{
+		ID:                spdx23.IDPrefix + n.Id,
+		Name:              n.Name,
+		CopyrightText:     orNoAssertion(n.Copyright),
+		Comment:           n.Comment,
+		LicenseConcluded:  orNoAssertion(n.LicenseConcluded),
+		LicenseComments:   n.LicenseComments,
+		Description:       n.Description,
+		FileTypes:         n.FileTypes,
+		LicenseInfoInFile: n.Licenses,
+		Checksums:         spdx23Checksums(n.Hashes),
+	}
+	if len(n.Attribution) > 0 {
+		f.Attribution = &n.Attribution
+	}
+	return f
+}
+
+// spdx23Checksums converts the hashes of a node, sorted by algorithm
+func spdx23Checksums(hashes map[string]string) []spdx23.Checksum {
+	algos := []string{}
+	for algo := range hashes {
+		algos = append(algos, algo)
+	}
+	sort.Strings(algos)
+	checksums := []spdx23.Checksum{}
+	for _, algo := range algos {
+		checksums = append(checksums, spdx23.Checksum{
+			Algorithm: spdxChecksumAlgorithm(algo),
+			Value:     hashes[algo],
+		})
+	}
+	return checksums
+}
+
+// spdxChecksumAlgorithms are the SPDX names of the algorithms that have
+// a hyphen, keyed by their normalized name.
+var spdxChecksumAlgorithms = map[string]string{
+	"SHA3256":    "SHA3-256",
+	"SHA3384":    "SHA3-384",
+	"SHA3512":    "SHA3-512",
+	"BLAKE2B256": "BLAKE2b-256",
+	"BLAKE2B384": "BLAKE2b-384",
+	"BLAKE2B512": "BLAKE2b-512",
+}
+
+// spdxChecksumAlgorithm returns the SPDX name of a hash algorithm
+func spdxChecksumAlgorithm(algo string) string {
+	algo = sbom.NormalizeHashAlgorithm(algo)
+	if name, ok := spdxChecksumAlgorithms[algo]; ok {
+		return name
+	}
+	return algo
+}
+
+// personToActor returns the SPDX actor string of a person
+func personToActor(p *sbom.Person) string {
+	actorType := "Person"
+	if p.IsOrg {
+		actorType = "Organization"
+	}
+	actor := actorType + ": " + p.Name
+	if p.Email != "" {
+		actor += " (" + p.Email + ")"
+	}
+	return actor
+}
+
+func orNoAssertion(s string) string {
+	if s == "" {
+		return spdx.NOASSERTION
+	}
+	return s
+}
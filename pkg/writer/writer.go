@@ -1,10 +1,13 @@
 package writer
 
 import (
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/onesbom/onesbom/pkg/formats"
 	"github.com/puerco/protobom/pkg/sbom"
 	"github.com/puerco/protobom/pkg/writer/options"
 )
@@ -28,26 +31,111 @@ type Writer struct {
 }
 
 func (w *Writer) WriteStream(bom *sbom.Document, wr io.WriteCloser) error {
+	_, err := w.WriteStreamReport(bom, wr)
+	return err
+}
+
+// WriteStreamReport writes the document to wr and returns the degradation
+// of its data in the output format.
+func (w *Writer) WriteStreamReport(bom *sbom.Document, wr io.WriteCloser) (Degradation, error) {
 	if bom == nil {
-		return errors.New("unable to write sbom to stream, SBOM is nil")
+		return nil, errors.New("unable to write sbom to stream, SBOM is nil")
+	}
+
+	out, err := compressWriter(w.Options.Compression, wr)
+	if err != nil {
+		return nil, err
+	}
+
+	deg := Degradation{}
+	switch w.Options.Attestation {
+	case options.AttestationNone:
+		err = w.serialize(bom, out, deg)
+	case options.AttestationStatement, options.AttestationEnvelope:
+		err = w.writeAttestation(bom, out, deg)
+	default:
+		err = fmt.Errorf("unknown attestation wrapper %q", w.Options.Attestation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("closing %s stream: %w", w.Options.Compression, err)
+	}
+	return deg, nil
+}
+
+// serialize renders the document in the writer format
+func (w *Writer) serialize(bom *sbom.Document, wr io.WriteCloser, deg Degradation) error {
+	// SPDX is only rendered by the streaming serializer
+	if w.Options.Format == formats.SPDX23JSON {
+		if err := w.writeStreamed(bom, wr, deg); err != nil {
+			return fmt.Errorf("serializing sbom: %w", err)
+		}
+		return nil
 	}
 	s, err := w.impl.GetFormatSerializer(w.Options.Format)
 	if err != nil {
 		return fmt.Errorf("getting serializer: %w", err)
 	}
 
-	if err := w.impl.SerializeSBOM(w.Options, s, bom, wr); err != nil {
+	if err := w.impl.SerializeSBOM(w.Options, s, bom, wr, deg); err != nil {
 		return fmt.Errorf("serializing sbom: %w", err)
 	}
 
 	return nil
 }
 
+// WriteFile writes the document to a file. Files with the .gz extension
+// are gzip compressed.
 func (w *Writer) WriteFile(bom *sbom.Document, path string) error {
+	_, err := w.WriteFileReport(bom, path)
+	return err
+}
+
+// WriteFileReport writes the document to a file and returns the
+// degradation of its data in the output format.
+func (w *Writer) WriteFileReport(bom *sbom.Document, path string) (Degradation, error) {
 	f, err := w.impl.OpenFile(path)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer f.Close()
+
+	fw := w
+	if strings.HasSuffix(path, ".gz") && w.Options.Compression == options.CompressionNone {
+		fw = &Writer{impl: w.impl, Options: w.Options}
+		fw.Options.Compression = options.CompressionGzip
 	}
 
-	return w.WriteStream(bom, f)
+	deg, err := fw.WriteStreamReport(bom, f)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("closing file: %w", err)
+	}
+	return deg, nil
+}
+
+// nopCloser passes the writes to the underlying writer without closing it
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// compressWriter returns a writer compressing the data written to wr with
+// the algorithm. Closing it flushes the compressed stream but does not
+// close wr.
+func compressWriter(algorithm string, wr io.Writer) (io.WriteCloser, error) {
+	switch algorithm {
+	case options.CompressionNone:
+		return nopCloser{wr}, nil
+	case options.CompressionGzip:
+		return gzip.NewWriter(wr), nil
+	default:
+		return nil, fmt.Errorf("unsupported output compression %q", algorithm)
+	}
 }